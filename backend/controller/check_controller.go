@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"database/sql"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/koloo91/monhttp/model"
 	"github.com/koloo91/monhttp/service"
@@ -15,6 +17,10 @@ type GetChecksQueryParameter struct {
 	ReduceByFactor *int       `form:"reduceByFactor" binding:"required"`
 }
 
+type CheckServiceQueryParameter struct {
+	Persist bool `form:"persist"`
+}
+
 func getChecks(ctx *gin.Context) {
 	serviceId := ctx.Param("id")
 
@@ -60,5 +66,66 @@ func getIsOnline(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, model.IsOnlineVo{Online: isOnline})
+	isFlapping, err := service.GetIsFlapping(ctx.Request.Context(), serviceId)
+	if err != nil {
+		log.Errorf("Unable to get is flapping value from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	isDegraded, err := service.GetIsDegraded(ctx.Request.Context(), serviceId)
+	if err != nil {
+		log.Errorf("Unable to get is degraded value from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.IsOnlineVo{Online: isOnline, Flapping: isFlapping, Degraded: isDegraded})
+}
+
+func checkService(ctx *gin.Context) {
+	serviceId := ctx.Param("id")
+
+	var queryParameter CheckServiceQueryParameter
+	if err := ctx.ShouldBindQuery(&queryParameter); err != nil {
+		log.Errorf("Unable to get query parameter: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	check, failure, err := service.CheckServiceNow(ctx.Request.Context(), serviceId, queryParameter.Persist)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Service with id '%s' not found", serviceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		if errors.Is(err, service.ErrServicePaused) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to check service with id '%s' - '%s'", serviceId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapCheckResultToVo(check, failure, queryParameter.Persist))
+}
+
+func getSloStatus(ctx *gin.Context) {
+	serviceId := ctx.Param("id")
+
+	status, err := service.GetSloStatus(ctx.Request.Context(), serviceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrSloNotConfigured) {
+			log.Infof("SLO of service with id '%s' not found", serviceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to get SLO status from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapSloStatusEntityToVo(status))
 }
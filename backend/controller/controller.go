@@ -54,12 +54,23 @@ func SetupRoutes() *gin.Engine {
 		apiGroup.GET("/services/:id", getService)
 		apiGroup.PUT("/services/:id", putService)
 		apiGroup.DELETE("/services/:id", deleteService)
+		apiGroup.POST("/services/test", testService)
+		apiGroup.GET("/services/dependencies", getServiceDependencies)
+	}
+
+	{
+		apiGroup.POST("/services/pause", pauseServices)
+		apiGroup.POST("/services/resume", resumeServices)
+		apiGroup.POST("/services/:id/pause", pauseService)
+		apiGroup.POST("/services/:id/resume", resumeService)
 	}
 
 	{
 		apiGroup.GET("/services/:id/checks", getChecks)
 		apiGroup.GET("/services/:id/average", getAverage)
 		apiGroup.GET("/services/:id/online", getIsOnline)
+		apiGroup.GET("/services/:id/slo", getSloStatus)
+		apiGroup.POST("/services/:id/check", checkService)
 	}
 
 	{
@@ -69,12 +80,45 @@ func SetupRoutes() *gin.Engine {
 	}
 
 	{
+		apiGroup.GET("/notifier-types", getNotifierTypes)
+		apiGroup.POST("/notifiers", postNotifier)
 		apiGroup.GET("/notifiers", getNotifiers)
+		apiGroup.GET("/notifiers/:id", getNotifier)
 		apiGroup.PUT("/notifiers/:id", updateNotifier)
+		apiGroup.DELETE("/notifiers/:id", deleteNotifier)
 		apiGroup.POST("/notifiers/:id/test/up", testNotifierUpTemplate)
 		apiGroup.POST("/notifiers/:id/test/down", testNotifierDownTemplate)
 	}
 
+	{
+		apiGroup.GET("/notifications", getNotifications)
+	}
+
+	{
+		apiGroup.POST("/escalation-policies", postEscalationPolicy)
+		apiGroup.GET("/escalation-policies", getEscalationPolicies)
+		apiGroup.GET("/escalation-policies/:id", getEscalationPolicy)
+		apiGroup.PUT("/escalation-policies/:id", putEscalationPolicy)
+		apiGroup.DELETE("/escalation-policies/:id", deleteEscalationPolicy)
+	}
+
+	{
+		apiGroup.GET("/incidents", getIncidents)
+		apiGroup.GET("/incidents/statistics", getIncidentStatistics)
+		apiGroup.GET("/incidents/:id", getIncident)
+		apiGroup.POST("/incidents/:id/acknowledge", acknowledgeIncident)
+		apiGroup.POST("/incidents/:id/notes", postIncidentNote)
+		apiGroup.POST("/services/:id/acknowledge", acknowledgeServiceIncident)
+	}
+
+	{
+		apiGroup.POST("/maintenances", postMaintenance)
+		apiGroup.GET("/maintenances", getMaintenances)
+		apiGroup.GET("/maintenances/:id", getMaintenance)
+		apiGroup.PUT("/maintenances/:id", putMaintenance)
+		apiGroup.DELETE("/maintenances/:id", deleteMaintenance)
+	}
+
 	{
 		apiGroup.POST("/import", importCsv)
 	}
@@ -151,6 +195,7 @@ func basicAuth() gin.HandlerFunc {
 				return
 			}
 
+			ctx.Set(gin.AuthUserKey, usernameAndPassword[0])
 			ctx.Next()
 			return
 		}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/service"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func postEscalationPolicy(ctx *gin.Context) {
+	var vo model.EscalationPolicyVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapEscalationPolicyVoToEntity(vo)
+	createdEntity, err := service.CreateEscalationPolicy(ctx.Request.Context(), entity)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownNotifier) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to store escalation policy into database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.MapEscalationPolicyEntityToVo(createdEntity))
+}
+
+func getEscalationPolicies(ctx *gin.Context) {
+	policies, err := service.GetEscalationPolicies(ctx.Request.Context())
+	if err != nil {
+		log.Errorf("Unable to get escalation policies from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.EscalationPolicyWrapperVo{Data: model.MapEscalationPolicyEntitiesToVos(policies)})
+}
+
+func getEscalationPolicy(ctx *gin.Context) {
+	policyId := ctx.Param("id")
+	policy, err := service.GetEscalationPolicyById(ctx.Request.Context(), policyId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Escalation policy with id '%s' not found", policyId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to get escalation policy from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapEscalationPolicyEntityToVo(policy))
+}
+
+func putEscalationPolicy(ctx *gin.Context) {
+	policyId := ctx.Param("id")
+
+	var requestBody model.EscalationPolicyVo
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapEscalationPolicyVoToEntity(requestBody)
+	updatedEntity, err := service.UpdateEscalationPolicyById(ctx.Request.Context(), policyId, entity)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownNotifier) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Escalation policy with id '%s' not found", policyId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to update escalation policy in database with id '%s' - '%s'", policyId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapEscalationPolicyEntityToVo(updatedEntity))
+}
+
+func deleteEscalationPolicy(ctx *gin.Context) {
+	policyId := ctx.Param("id")
+	if err := service.DeleteEscalationPolicyById(ctx.Request.Context(), policyId); err != nil {
+		log.Errorf("Unable to delete escalation policy from database with id '%s' - '%s'", policyId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+	ctx.JSON(http.StatusNoContent, "")
+}
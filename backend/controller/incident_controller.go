@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/service"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+type GetIncidentsQueryParameter struct {
+	PageSize  *int   `form:"pageSize" binding:"required"`
+	Page      *int   `form:"page" binding:"required"`
+	ServiceId string `form:"serviceId"`
+	Status    string `form:"status" binding:"omitempty,oneof=OPEN RESOLVED"`
+}
+
+type GetIncidentStatisticsQueryParameter struct {
+	ServiceId string     `form:"serviceId"`
+	From      *time.Time `form:"from" binding:"required"`
+	To        *time.Time `form:"to" binding:"required"`
+}
+
+func getIncidents(ctx *gin.Context) {
+	var queryParameter GetIncidentsQueryParameter
+	if err := ctx.ShouldBindQuery(&queryParameter); err != nil {
+		log.Errorf("Unable to get query parameter: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	incidents, err := service.GetIncidents(ctx.Request.Context(), queryParameter.ServiceId, queryParameter.Status,
+		*queryParameter.PageSize, *queryParameter.Page)
+	if err != nil {
+		log.Errorf("Unable to get incidents from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	incidentsCount, err := service.GetIncidentsCount(ctx.Request.Context(), queryParameter.ServiceId, queryParameter.Status)
+	if err != nil {
+		log.Errorf("Unable to get incidents count from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.IncidentWrapperVo{
+		Data:       model.MapIncidentEntitiesToVos(incidents),
+		TotalCount: incidentsCount,
+		PageSize:   *queryParameter.PageSize,
+		Page:       *queryParameter.Page,
+	})
+}
+
+func getIncident(ctx *gin.Context) {
+	incidentId := ctx.Param("id")
+	incident, err := service.GetIncidentById(ctx.Request.Context(), incidentId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Incident with id '%s' not found", incidentId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to get incident from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapIncidentEntityToVo(incident))
+}
+
+func getIncidentStatistics(ctx *gin.Context) {
+	var queryParameter GetIncidentStatisticsQueryParameter
+	if err := ctx.ShouldBindQuery(&queryParameter); err != nil {
+		log.Errorf("Unable to get query parameter: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	statistics, err := service.GetIncidentStatistics(ctx.Request.Context(), queryParameter.ServiceId,
+		*queryParameter.From, *queryParameter.To)
+	if err != nil {
+		log.Errorf("Unable to get incident statistics from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapIncidentStatisticsToVo(statistics))
+}
+
+func acknowledgeIncident(ctx *gin.Context) {
+	incidentId := ctx.Param("id")
+	incident, err := service.AcknowledgeIncident(ctx.Request.Context(), incidentId, ctx.GetString(gin.AuthUserKey))
+	if err != nil {
+		handleAcknowledgeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapIncidentEntityToVo(incident))
+}
+
+func acknowledgeServiceIncident(ctx *gin.Context) {
+	serviceId := ctx.Param("id")
+	incident, err := service.AcknowledgeServiceIncident(ctx.Request.Context(), serviceId, ctx.GetString(gin.AuthUserKey))
+	if err != nil {
+		handleAcknowledgeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapIncidentEntityToVo(incident))
+}
+
+func handleAcknowledgeError(ctx *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrNoOpenIncident) {
+		log.Infof("Incident not found: '%s'", err)
+		ctx.JSON(http.StatusNotFound, toApiError(err))
+		return
+	}
+	if errors.Is(err, service.ErrIncidentResolved) {
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+	log.Errorf("Unable to acknowledge incident: '%s'", err)
+	ctx.JSON(http.StatusInternalServerError, toApiError(err))
+}
+
+func postIncidentNote(ctx *gin.Context) {
+	incidentId := ctx.Param("id")
+
+	var vo model.IncidentNoteVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapIncidentNoteVoToEntity(vo, incidentId, ctx.GetString(gin.AuthUserKey))
+	note, err := service.AddIncidentNote(ctx.Request.Context(), entity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Incident with id '%s' not found", incidentId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to store incident note into database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.MapIncidentNoteEntityToVo(note))
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/service"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func postMaintenance(ctx *gin.Context) {
+	var vo model.MaintenanceVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapMaintenanceVoToEntity(vo)
+	createdEntity, err := service.CreateMaintenance(ctx.Request.Context(), entity)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMaintenanceDuration) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to store maintenance into database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.MapMaintenanceEntityToVo(createdEntity))
+}
+
+func getMaintenances(ctx *gin.Context) {
+	maintenances, err := service.GetMaintenances(ctx.Request.Context())
+	if err != nil {
+		log.Errorf("Unable to get maintenances from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MaintenanceWrapperVo{Data: model.MapMaintenanceEntitiesToVos(maintenances)})
+}
+
+func getMaintenance(ctx *gin.Context) {
+	maintenanceId := ctx.Param("id")
+	maintenance, err := service.GetMaintenanceById(ctx.Request.Context(), maintenanceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Maintenance with id '%s' not found", maintenanceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to get maintenance from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapMaintenanceEntityToVo(maintenance))
+}
+
+func putMaintenance(ctx *gin.Context) {
+	maintenanceId := ctx.Param("id")
+
+	var requestBody model.MaintenanceVo
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapMaintenanceVoToEntity(requestBody)
+	updatedEntity, err := service.UpdateMaintenanceById(ctx.Request.Context(), maintenanceId, entity)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMaintenanceDuration) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Maintenance with id '%s' not found", maintenanceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to update maintenance in database with id '%s' - '%s'", maintenanceId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapMaintenanceEntityToVo(updatedEntity))
+}
+
+func deleteMaintenance(ctx *gin.Context) {
+	maintenanceId := ctx.Param("id")
+	if err := service.DeleteMaintenanceById(ctx.Request.Context(), maintenanceId); err != nil {
+		log.Errorf("Unable to delete maintenance from database with id '%s' - '%s'", maintenanceId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+	ctx.JSON(http.StatusNoContent, "")
+}
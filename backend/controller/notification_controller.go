@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/service"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type GetNotificationsQueryParameter struct {
+	PageSize   *int                     `form:"pageSize" binding:"required"`
+	Page       *int                     `form:"page" binding:"required"`
+	ServiceId  string                   `form:"serviceId"`
+	NotifierId string                   `form:"notifierId"`
+	Status     model.NotificationStatus `form:"status" binding:"omitempty,oneof=PENDING SENT FAILED SKIPPED COALESCED HELD"`
+}
+
+func getNotifications(ctx *gin.Context) {
+	var queryParameter GetNotificationsQueryParameter
+	if err := ctx.ShouldBindQuery(&queryParameter); err != nil {
+		log.Errorf("Unable to get query parameter: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	notifications, err := service.GetNotifications(ctx.Request.Context(), queryParameter.ServiceId, queryParameter.NotifierId,
+		queryParameter.Status, *queryParameter.PageSize, *queryParameter.Page)
+	if err != nil {
+		log.Errorf("Unable to get notifications from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	notificationCount, err := service.GetNotificationsCount(ctx.Request.Context(), queryParameter.ServiceId,
+		queryParameter.NotifierId, queryParameter.Status)
+	if err != nil {
+		log.Errorf("Unable to get notifications count from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.NotificationWrapperVo{
+		Data:       model.MapNotificationEntitiesToVos(notifications),
+		TotalCount: notificationCount,
+		PageSize:   *queryParameter.PageSize,
+		Page:       *queryParameter.Page,
+	})
+}
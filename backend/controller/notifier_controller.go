@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"database/sql"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/koloo91/monhttp/model"
 	"github.com/koloo91/monhttp/service"
@@ -14,23 +16,87 @@ func getNotifiers(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, model.NotifierWrapperVo{Data: notifiersVo})
 }
 
+func getNotifierTypes(ctx *gin.Context) {
+	notifierTypes := service.GetNotifierTypes()
+	ctx.JSON(http.StatusOK, model.NotifierTypeWrapperVo{Data: model.MapNotifierTypesToVos(notifierTypes)})
+}
+
+func postNotifier(ctx *gin.Context) {
+	var vo model.NotifierInstanceVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	notifier, validationErrors, err := service.CreateNotifier(ctx.Request.Context(), model.MapNotifierInstanceVoToEntity(vo))
+	if err != nil {
+		if isNotifierValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, toApiErrorWithErrors(err, validationErrors))
+			return
+		}
+		log.Errorf("Unable to store notifier into database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.MapNotifierToVo(notifier))
+}
+
+func getNotifier(ctx *gin.Context) {
+	id := ctx.Param("id")
+	notifier, err := service.GetNotifierById(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Notifier with id '%s' not found", id)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to get notifier from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapNotifierToVo(notifier))
+}
+
 func updateNotifier(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	var body map[string]interface{}
-	if err := ctx.ShouldBindJSON(&body); err != nil {
+	var vo model.NotifierInstanceVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
 		log.Errorf("Unable to bind json body: '%s'", err)
 		ctx.JSON(http.StatusBadRequest, toApiError(err))
 		return
 	}
 
-	if err := service.UpdateNotifier(id, body); err != nil {
+	notifier, validationErrors, err := service.UpdateNotifierById(ctx.Request.Context(), id, model.MapNotifierInstanceVoToEntity(vo))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Notifier with id '%s' not found", id)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		if isNotifierValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, toApiErrorWithErrors(err, validationErrors))
+			return
+		}
 		log.Errorf("Unable to update notifier '%s' - '%s'", id, err)
 		ctx.JSON(http.StatusInternalServerError, toApiError(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, "")
+	ctx.JSON(http.StatusOK, model.MapNotifierToVo(notifier))
+}
+
+func deleteNotifier(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if err := service.DeleteNotifierById(ctx.Request.Context(), id); err != nil {
+		log.Errorf("Unable to delete notifier from database with id '%s' - '%s'", id, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+	ctx.JSON(http.StatusNoContent, "")
 }
 
 func testNotifierUpTemplate(ctx *gin.Context) {
@@ -43,7 +109,7 @@ func testNotifierUpTemplate(ctx *gin.Context) {
 		return
 	}
 
-	if err := service.TestNotifierUpTemplate(id, body); err != nil {
+	if err := service.TestNotifierUpTemplate(ctx.Request.Context(), id, body); err != nil {
 		log.Errorf("Unable to test notifier '%s' - '%s'", id, err)
 		ctx.JSON(http.StatusInternalServerError, toApiError(err))
 		return
@@ -62,7 +128,7 @@ func testNotifierDownTemplate(ctx *gin.Context) {
 		return
 	}
 
-	if err := service.TestNotifierDownTemplate(id, body); err != nil {
+	if err := service.TestNotifierDownTemplate(ctx.Request.Context(), id, body); err != nil {
 		log.Errorf("Unable to update notifier '%s' - '%s'", id, err)
 		ctx.JSON(http.StatusInternalServerError, toApiError(err))
 		return
@@ -70,3 +136,8 @@ func testNotifierDownTemplate(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, "")
 }
+
+func isNotifierValidationError(err error) bool {
+	return errors.Is(err, service.ErrUnknownNotifierType) || errors.Is(err, service.ErrInvalidNotificationSchedule) ||
+		errors.Is(err, service.ErrUnknownNotifier) || errors.Is(err, service.ErrInvalidNotifierConfig)
+}
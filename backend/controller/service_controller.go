@@ -3,13 +3,20 @@ package controller
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/koloo91/monhttp/model"
 	"github.com/koloo91/monhttp/service"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 )
 
+var (
+	errNoServicesSelected = errors.New("either 'ids' must not be empty or 'all' must be true")
+	errInvalidServiceIds  = errors.New("'ids' contains invalid service ids")
+)
+
 type GetServicesQueryParameter struct {
 	PageSize *int `form:"pageSize" binding:"required"`
 	Page     *int `form:"page" binding:"required"`
@@ -30,6 +37,10 @@ func postService(ctx *gin.Context) {
 	entity := model.MapServiceVoToEntity(vo)
 	createdEntity, err := service.CreateService(ctx.Request.Context(), entity)
 	if err != nil {
+		if isServiceValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
 		log.Errorf("Unable to store service into database: '%s'", err)
 		ctx.JSON(http.StatusInternalServerError, toApiError(err))
 		return
@@ -101,6 +112,10 @@ func putService(ctx *gin.Context) {
 	serviceEntity := model.MapServiceVoToEntity(requestBody)
 	serviceEntity, err := service.UpdateServiceById(ctx.Request.Context(), serviceId, serviceEntity)
 	if err != nil {
+		if isServiceValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, toApiError(err))
+			return
+		}
 		log.Errorf("Unable to update service in database with id '%s' - '%s'", serviceId, err)
 		ctx.JSON(http.StatusInternalServerError, toApiError(err))
 		return
@@ -119,3 +134,144 @@ func deleteService(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusNoContent, "")
 }
+
+func pauseService(ctx *gin.Context) {
+	serviceId := ctx.Param("id")
+	serviceEntity, err := service.PauseServiceById(ctx.Request.Context(), serviceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Service with id '%s' not found", serviceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to pause service with id '%s' - '%s'", serviceId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapServiceEntityToVo(serviceEntity))
+}
+
+func resumeService(ctx *gin.Context) {
+	serviceId := ctx.Param("id")
+	serviceEntity, err := service.ResumeServiceById(ctx.Request.Context(), serviceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Infof("Service with id '%s' not found", serviceId)
+			ctx.JSON(http.StatusNotFound, toApiError(err))
+			return
+		}
+		log.Errorf("Unable to resume service with id '%s' - '%s'", serviceId, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapServiceEntityToVo(serviceEntity))
+}
+
+func pauseServices(ctx *gin.Context) {
+	requestBody, ok := bindServiceIds(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := service.PauseServices(ctx.Request.Context(), requestBody.Ids, requestBody.All)
+	if err != nil {
+		log.Errorf("Unable to pause services: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"count": count})
+}
+
+func resumeServices(ctx *gin.Context) {
+	requestBody, ok := bindServiceIds(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := service.ResumeServices(ctx.Request.Context(), requestBody.Ids, requestBody.All)
+	if err != nil {
+		log.Errorf("Unable to resume services: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"count": count})
+}
+
+// bindServiceIds binds the services of a bulk operation. It responds with a bad request and returns false if no
+// service is selected or an id is not a valid uuid.
+func bindServiceIds(ctx *gin.Context) (model.ServiceIdsVo, bool) {
+	var requestBody model.ServiceIdsVo
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return requestBody, false
+	}
+
+	if !requestBody.All && len(requestBody.Ids) == 0 {
+		ctx.JSON(http.StatusBadRequest, toApiError(errNoServicesSelected))
+		return requestBody, false
+	}
+
+	if requestBody.All {
+		return requestBody, true
+	}
+
+	validationErrors := make([]interface{}, 0)
+	for i, id := range requestBody.Ids {
+		if _, err := uuid.Parse(id); err != nil {
+			validationErrors = append(validationErrors, model.FieldErrorVo{
+				Field:   fmt.Sprintf("ids[%d]", i),
+				Message: fmt.Sprintf("'%s' is not a valid id", id),
+			})
+		}
+	}
+	if len(validationErrors) > 0 {
+		ctx.JSON(http.StatusBadRequest, toApiErrorWithErrors(errInvalidServiceIds, validationErrors))
+		return requestBody, false
+	}
+	return requestBody, true
+}
+
+func testService(ctx *gin.Context) {
+	var vo model.ServiceVo
+	if err := ctx.ShouldBindJSON(&vo); err != nil {
+		log.Errorf("Unable to bind json body: '%s'", err)
+		ctx.JSON(http.StatusBadRequest, toApiError(err))
+		return
+	}
+
+	entity := model.MapServiceVoToEntity(vo)
+	result, validationErrors, err := service.TestService(entity)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidServiceDefinition) {
+			ctx.JSON(http.StatusBadRequest, toApiErrorWithErrors(err, validationErrors))
+			return
+		}
+		log.Errorf("Unable to test service '%s' - '%s'", entity.Name, err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.MapProbeResultToVo(result))
+}
+
+func getServiceDependencies(ctx *gin.Context) {
+	dependencies, err := service.GetServiceDependencies(ctx.Request.Context())
+	if err != nil {
+		log.Errorf("Unable to get service dependencies from database: '%s'", err)
+		ctx.JSON(http.StatusInternalServerError, toApiError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.ServiceDependencyWrapperVo{Data: model.MapServiceDependencyEntitiesToVos(dependencies)})
+}
+
+func isServiceValidationError(err error) bool {
+	return errors.Is(err, service.ErrDependencyCycle) || errors.Is(err, service.ErrUnknownParentService) ||
+		errors.Is(err, service.ErrUnknownEscalationPolicy) || errors.Is(err, service.ErrUnknownNotifier) ||
+		errors.Is(err, service.ErrInvalidNotificationTemplate)
+}
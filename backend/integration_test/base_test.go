@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/koloo91/monhttp/controller"
+	"github.com/koloo91/monhttp/notifier"
 	"github.com/koloo91/monhttp/repository"
 	"github.com/koloo91/monhttp/service"
 	"github.com/stretchr/testify/assert"
@@ -53,6 +54,8 @@ func (suite *MonHttpTestSuite) SetupSuite() {
 
 	assert.Nil(suite.T(), service.AddUser("admin", "admin"))
 
+	service.SetNotificationSystem(notifier.NewNotificationSystem())
+
 	suite.router = controller.SetupRoutes()
 }
 
@@ -0,0 +1,55 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) TestCheckServiceShouldReturnNotFoundForUnknownService() {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/"+uuid.New().String()+"/check", nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestCheckServiceShouldReturnFailureWithoutPersisting() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Unreachable",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"httpMethod":              "GET",
+		"requestTimeoutInSeconds": 1,
+		"expectedHttpStatusCode":  200,
+		"notifiers":               []string{"global"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", "/api/services/"+createdService["id"].(string)+"/check", nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), false, responseBody["persisted"])
+	assert.Equal(suite.T(), true, responseBody["check"].(map[string]interface{})["isFailure"])
+	assert.NotNil(suite.T(), responseBody["failure"])
+}
@@ -0,0 +1,193 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) createEscalationPolicy(notifierIds ...interface{}) map[string]interface{} {
+	steps := make([]interface{}, 0, len(notifierIds))
+	for _, notifierId := range notifierIds {
+		steps = append(steps, map[string]interface{}{
+			"notifierIds":    []interface{}{notifierId},
+			"delayInMinutes": 15,
+		})
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":  "On call",
+		"steps": steps,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/escalation-policies", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	return responseBody
+}
+
+func (suite *MonHttpTestSuite) TestCreateEscalationPolicyShouldReturnCreated() {
+	first := suite.createNotifier(map[string]interface{}{"type": "webhook", "name": "First", "data": map[string]interface{}{}})
+	second := suite.createNotifier(map[string]interface{}{"type": "webhook", "name": "Second", "data": map[string]interface{}{}})
+
+	responseBody := suite.createEscalationPolicy(first["id"], second["id"])
+
+	assert.NotEmpty(suite.T(), responseBody["id"])
+	assert.Equal(suite.T(), "On call", responseBody["name"])
+
+	steps := responseBody["steps"].([]interface{})
+	assert.Equal(suite.T(), 2, len(steps))
+	assert.Equal(suite.T(), []interface{}{second["id"]}, steps[1].(map[string]interface{})["notifierIds"])
+	assert.Equal(suite.T(), float64(15), steps[1].(map[string]interface{})["delayInMinutes"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateEscalationPolicyShouldReturnBadRequestForUnknownNotifier() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":  "On call",
+		"steps": []interface{}{map[string]interface{}{"notifierIds": []string{"unknown"}, "delayInMinutes": 5}},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/escalation-policies", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestCreateEscalationPolicyShouldReturnBadRequestWithoutSteps() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":  "On call",
+		"steps": []interface{}{},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/escalation-policies", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnBadRequestForUnknownEscalationPolicy() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "With unknown policy",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"requestTimeoutInSeconds": 1,
+		"escalationPolicyId":      "7a1c40a4-2a5b-4b8e-9f0e-3c2d4b1a0f11",
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestServiceDownShouldNotifyFirstEscalationStepUntilAcknowledged() {
+	first := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Telegram first",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/first"},
+	})
+	second := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Team lead",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/second"},
+	})
+	policy := suite.createEscalationPolicy(first["id"], second["id"])
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Unreachable with escalation",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"escalationPolicyId":          policy["id"],
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+	assert.Equal(suite.T(), policy["id"], createdService["escalationPolicyId"])
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", fmt.Sprintf("/api/services/%s/check?persist=true", createdService["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var notifications map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &notifications))
+	assert.Equal(suite.T(), float64(1), notifications["totalCount"])
+
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), first["id"], notification["notifierId"])
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", fmt.Sprintf("/api/services/%s/acknowledge", createdService["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var incident map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &incident))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), policy["id"], incident["escalationPolicyId"])
+	assert.Equal(suite.T(), float64(0), incident["escalationStep"])
+	assert.Equal(suite.T(), user, incident["acknowledgedBy"])
+	assert.NotNil(suite.T(), incident["acknowledgedAt"])
+	assert.Nil(suite.T(), incident["nextEscalationAt"])
+}
+
+func (suite *MonHttpTestSuite) TestDeleteEscalationPolicyShouldReturnNoContent() {
+	notifier := suite.createNotifier(map[string]interface{}{"type": "webhook", "name": "Hook", "data": map[string]interface{}{}})
+	policy := suite.createEscalationPolicy(notifier["id"])
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/escalation-policies/%s", policy["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusNoContent, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", fmt.Sprintf("/api/escalation-policies/%s", policy["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,78 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+)
+
+func (suite *MonHttpTestSuite) TestCreateExecNotifierShouldReturnBadRequestUnlessEnabled() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"type": "exec",
+		"name": "Restart api",
+		"data": map[string]interface{}{"enabled": true, "command": "/usr/bin/docker", "arguments": "restart\napi"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/notifiers", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestExecNotifierShouldStoreOutputInDeliveryLog() {
+	assert.Nil(suite.T(), os.Setenv("EXEC_NOTIFIER_ENABLED", "true"))
+	defer os.Unsetenv("EXEC_NOTIFIER_ENABLED")
+
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "exec",
+		"name": "Script",
+		"data": map[string]interface{}{
+			"enabled":   true,
+			"command":   "/bin/sh",
+			"arguments": "-c\necho \"$MONHTTP_EVENT $MONHTTP_SERVICE_NAME\"",
+		},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Scripted",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	path := fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"])
+	_, notifications := suite.getJson(path)
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	service.DeliverNotification(notification["id"].(string))
+
+	_, notifications = suite.getJson(path)
+	notification = notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), "SENT", notification["status"])
+	assert.Equal(suite.T(), "down Scripted", notification["output"])
+}
@@ -0,0 +1,99 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+)
+
+func (suite *MonHttpTestSuite) TestFlappingServiceShouldSendSingleFlappingNotification() {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// down, up, down, up, ...
+		if atomic.AddInt32(&requests, 1)%2 == 1 {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Flapping service",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    server.URL,
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+		"flappingThreshold":           2,
+		"flappingWindow":              10,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+	assert.Equal(suite.T(), false, createdService["isFlapping"])
+
+	for i := 0; i < 6; i++ {
+		suite.checkServiceAndPersist(createdService["id"])
+	}
+
+	code, service := suite.getJson(fmt.Sprintf("/api/services/%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), true, service["isFlapping"])
+
+	code, online := suite.getJson(fmt.Sprintf("/api/services/%s/online", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), true, online["flapping"])
+
+	code, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+
+	// down, up and down before the service starts flapping with the fourth check
+	assert.Equal(suite.T(), float64(4), notifications["totalCount"])
+
+	latest := notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), false, latest["isUpNotification"])
+	assert.Contains(suite.T(), latest["reason"], "Service is flapping")
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnBadRequestForInvalidFlappingWindow() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Invalid flapping window",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"requestTimeoutInSeconds": 1,
+		"flappingThreshold":       2,
+		"flappingWindow":          1000,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,202 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+)
+
+func (suite *MonHttpTestSuite) checkServiceAndPersist(serviceId interface{}) {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", fmt.Sprintf("/api/services/%s/check?persist=true", serviceId), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) getJson(path string) (int, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+	return recorder.Code, responseBody
+}
+
+func (suite *MonHttpTestSuite) TestAcknowledgedIncidentShouldStopRepeatedNotifications() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                          "Unreachable with repeated notifications",
+		"type":                          "HTTP",
+		"intervalInSeconds":             30,
+		"endpoint":                      "http://localhost:1",
+		"httpMethod":                    "GET",
+		"requestTimeoutInSeconds":       1,
+		"expectedHttpStatusCode":        200,
+		"enableNotifications":           true,
+		"notifyAfterNumberOfFailures":   1,
+		"continuouslySendNotifications": true,
+		"notifiers":                     []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+	suite.checkServiceAndPersist(createdService["id"])
+
+	code, incidents := suite.getJson(fmt.Sprintf("/api/incidents?page=0&pageSize=10&status=OPEN&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(1), incidents["totalCount"])
+
+	incident := incidents["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), float64(2), incident["failureCount"])
+	assert.Nil(suite.T(), incident["acknowledgedAt"])
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", fmt.Sprintf("/api/incidents/%s/acknowledge", incident["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), float64(2), notifications["totalCount"])
+
+	code, incident = suite.getJson(fmt.Sprintf("/api/incidents/%s", incident["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(3), incident["failureCount"])
+	assert.Equal(suite.T(), user, incident["acknowledgedBy"])
+	assert.NotNil(suite.T(), incident["acknowledgedAt"])
+}
+
+func (suite *MonHttpTestSuite) TestIncidentShouldOnlyBeOpenedAfterNumberOfFailures() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Unreachable after three failures",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"notifyAfterNumberOfFailures": 3,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, incidents := suite.getJson(fmt.Sprintf("/api/incidents?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), float64(0), incidents["totalCount"])
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, incidents = suite.getJson(fmt.Sprintf("/api/incidents?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), float64(1), incidents["totalCount"])
+
+	incident := incidents["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), float64(3), incident["failureCount"])
+}
+
+func (suite *MonHttpTestSuite) TestPostIncidentNoteShouldReturnCreated() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Unreachable with note",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"httpMethod":              "GET",
+		"requestTimeoutInSeconds": 1,
+		"expectedHttpStatusCode":  200,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, incidents := suite.getJson(fmt.Sprintf("/api/incidents?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	incident := incidents["data"].([]interface{})[0].(map[string]interface{})
+
+	requestBody, err = json.Marshal(map[string]interface{}{"text": "Restarting the database"})
+	assert.Nil(suite.T(), err)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", fmt.Sprintf("/api/incidents/%s/notes", incident["id"]), bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var note map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &note))
+
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+	assert.Equal(suite.T(), "Restarting the database", note["text"])
+	assert.Equal(suite.T(), user, note["author"])
+
+	_, incident = suite.getJson(fmt.Sprintf("/api/incidents/%s", incident["id"]))
+	notes := incident["notes"].([]interface{})
+	assert.Equal(suite.T(), 1, len(notes))
+	assert.Equal(suite.T(), "Restarting the database", notes[0].(map[string]interface{})["text"])
+
+	from := url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	to := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+	code, statistics := suite.getJson(fmt.Sprintf("/api/incidents/statistics?serviceId=%s&from=%s&to=%s", createdService["id"], from, to))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(1), statistics["count"])
+	assert.Equal(suite.T(), float64(0), statistics["resolvedCount"])
+}
+
+func (suite *MonHttpTestSuite) TestPostIncidentNoteShouldReturnNotFoundForUnknownIncident() {
+	requestBody, err := json.Marshal(map[string]interface{}{"text": "Looking into it"})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/incidents/7a1c40a4-2a5b-4b8e-9f0e-3c2d4b1a0f11/notes", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestAcknowledgeServiceShouldReturnNotFoundWithoutOpenIncident() {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/7a1c40a4-2a5b-4b8e-9f0e-3c2d4b1a0f11/acknowledge", nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,89 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+)
+
+func (suite *MonHttpTestSuite) TestSlowServiceShouldSendDegradedNotification() {
+	var slow int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.LoadInt32(&slow) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Slow service",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                server.URL,
+		"httpMethod":              "GET",
+		"requestTimeoutInSeconds": 1,
+		"expectedHttpStatusCode":  200,
+		"enableNotifications":     true,
+		"notifiers":               []interface{}{notifier["id"]},
+		"latencyThresholdInMs":    50,
+		"latencyPercentile":       95,
+		"latencyWindow":           2,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+	suite.checkServiceAndPersist(createdService["id"])
+
+	code, online := suite.getJson(fmt.Sprintf("/api/services/%s/online", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), true, online["online"])
+	assert.Equal(suite.T(), true, online["degraded"])
+
+	code, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(1), notifications["totalCount"])
+
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), false, notification["isUpNotification"])
+	assert.Equal(suite.T(), true, notification["isDegradedNotification"])
+	assert.Contains(suite.T(), notification["reason"], "above the threshold of 50ms")
+	assert.Contains(suite.T(), notification["payload"], `"event": "degraded"`)
+
+	atomic.StoreInt32(&slow, 0)
+	suite.checkServiceAndPersist(createdService["id"])
+	suite.checkServiceAndPersist(createdService["id"])
+
+	code, service := suite.getJson(fmt.Sprintf("/api/services/%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), false, service["isDegraded"])
+
+	_, notifications = suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), float64(2), notifications["totalCount"])
+
+	notification = notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), true, notification["isUpNotification"])
+	assert.Equal(suite.T(), true, notification["isDegradedNotification"])
+	assert.Contains(suite.T(), notification["reason"], "Latency is back to normal")
+	assert.Contains(suite.T(), notification["payload"], `"event": "normal"`)
+}
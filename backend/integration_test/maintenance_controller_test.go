@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (suite *MonHttpTestSuite) TestCreateMaintenanceShouldReturnUnauthorizedWithoutCredentials() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name": "Deploy",
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/maintenances", bytes.NewBuffer(requestBody))
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, recorder.Code)
+	assert.Equal(suite.T(), "invalid credentials", responseBody["message"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateMaintenanceShouldReturnCreated() {
+	startAt := time.Now().UTC().Truncate(time.Second)
+	endAt := startAt.Add(time.Hour)
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":       "Deploy",
+		"startAt":    startAt,
+		"endAt":      endAt,
+		"recurrence": "WEEKLY",
+		"skipChecks": true,
+		"tags":       []string{"production"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/maintenances", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+	assert.NotEmpty(suite.T(), responseBody["id"])
+	assert.Equal(suite.T(), "Deploy", responseBody["name"])
+	assert.Equal(suite.T(), "WEEKLY", responseBody["recurrence"])
+	assert.Equal(suite.T(), true, responseBody["skipChecks"])
+	assert.Equal(suite.T(), []interface{}{}, responseBody["serviceIds"])
+	assert.Equal(suite.T(), []interface{}{"production"}, responseBody["tags"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateMaintenanceShouldReturnErrorIfEndIsBeforeStart() {
+	startAt := time.Now().UTC()
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":    "Deploy",
+		"startAt": startAt,
+		"endAt":   startAt.Add(-time.Hour),
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/maintenances", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "Key: 'MaintenanceVo.EndAt' Error:Field validation for 'EndAt' failed on the 'gtfield' tag", responseBody["message"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateMaintenanceShouldReturnErrorIfLongerThanRecurrence() {
+	startAt := time.Now().UTC()
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":       "Deploy",
+		"startAt":    startAt,
+		"endAt":      startAt.Add(25 * time.Hour),
+		"recurrence": "DAILY",
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/maintenances", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "a recurring maintenance window must be shorter than its recurrence period", responseBody["message"])
+}
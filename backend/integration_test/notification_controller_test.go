@@ -0,0 +1,136 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+)
+
+func (suite *MonHttpTestSuite) TestGetNotificationsShouldReturnBadRequestWithoutPaging() {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/api/notifications", nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestPersistedFailureShouldQueueNotification() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Unreachable with notification",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", fmt.Sprintf("/api/services/%s/check?persist=true", createdService["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), float64(1), responseBody["totalCount"])
+
+	notification := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), notifier["id"], notification["notifierId"])
+	assert.Equal(suite.T(), "PENDING", notification["status"])
+	assert.Equal(suite.T(), false, notification["isUpNotification"])
+	assert.Equal(suite.T(), float64(0), notification["attempts"])
+	assert.Contains(suite.T(), notification["payload"], "Unreachable with notification")
+}
+
+func (suite *MonHttpTestSuite) TestUpNotificationShouldNotSupersedePendingDownNotification() {
+	var failing int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Briefly down",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    server.URL,
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+	atomic.StoreInt32(&failing, 0)
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), float64(2), notifications["totalCount"])
+
+	up := notifications["data"].([]interface{})[0].(map[string]interface{})
+	down := notifications["data"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(suite.T(), true, up["isUpNotification"])
+	assert.Equal(suite.T(), "PENDING", down["status"])
+
+	// the up notification waits for the down notification
+	service.DeliverNotification(up["id"].(string))
+
+	_, notifications = suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	up = notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), "PENDING", up["status"])
+	assert.Equal(suite.T(), float64(0), up["attempts"])
+}
@@ -0,0 +1,249 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/service"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) createNotifier(body map[string]interface{}) map[string]interface{} {
+	requestBody, err := json.Marshal(body)
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/notifiers", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	return responseBody
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldReturnCreated() {
+	responseBody := suite.createNotifier(map[string]interface{}{
+		"type": "telegram",
+		"name": "Team A",
+		"data": map[string]interface{}{
+			"enabled":  true,
+			"apiToken": "token",
+			"channel":  "4711",
+		},
+	})
+
+	assert.NotEmpty(suite.T(), responseBody["id"])
+	assert.NotEqual(suite.T(), "telegram", responseBody["id"])
+	assert.Equal(suite.T(), "telegram", responseBody["type"])
+	assert.Equal(suite.T(), "Team A", responseBody["name"])
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(suite.T(), true, data["enabled"])
+	assert.Equal(suite.T(), "4711", data["channel"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldAllowMultipleInstancesOfAType() {
+	first := suite.createNotifier(map[string]interface{}{"type": "telegram", "name": "Team A"})
+	second := suite.createNotifier(map[string]interface{}{"type": "telegram", "name": "Team B"})
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/api/notifiers", nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string][]map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	ids := make([]interface{}, 0)
+	for _, notifier := range responseBody["data"] {
+		ids = append(ids, notifier["id"])
+	}
+	assert.Contains(suite.T(), ids, first["id"])
+	assert.Contains(suite.T(), ids, second["id"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldReturnErrorForUnknownType() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"type": "carrier-pigeon",
+		"name": "Pigeons",
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/notifiers", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "unknown notifier type: 'carrier-pigeon'", responseBody["message"])
+}
+
+func (suite *MonHttpTestSuite) TestUpdateNotifierShouldKeepType() {
+	created := suite.createNotifier(map[string]interface{}{"type": "webhook", "name": "Hook"})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"type": "telegram",
+		"name": "Renamed hook",
+		"data": map[string]interface{}{"url": "https://example.com/hook"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", fmt.Sprintf("/api/notifiers/%s", created["id"]), bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), created["id"], responseBody["id"])
+	assert.Equal(suite.T(), "webhook", responseBody["type"])
+	assert.Equal(suite.T(), "Renamed hook", responseBody["name"])
+	assert.Equal(suite.T(), "https://example.com/hook", responseBody["data"].(map[string]interface{})["url"])
+}
+
+func (suite *MonHttpTestSuite) TestDeleteNotifierShouldReturnNoContent() {
+	created := suite.createNotifier(map[string]interface{}{"type": "webhook", "name": "Hook"})
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/notifiers/%s", created["id"]), nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusNoContent, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", fmt.Sprintf("/api/notifiers/%s", created["id"]), nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldStoreRateLimit() {
+	created := suite.createNotifier(map[string]interface{}{"type": "telegram", "name": "Throttled", "rateLimit": 20})
+	assert.Equal(suite.T(), float64(20), created["rateLimit"])
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", fmt.Sprintf("/api/notifiers/%s", created["id"]), nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), float64(20), responseBody["rateLimit"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldReturnBadRequestForNegativeRateLimit() {
+	requestBody, err := json.Marshal(map[string]interface{}{"type": "telegram", "name": "Negative", "rateLimit": -1})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/notifiers", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestGetNotifierTypesShouldReturnFormsOfAllTypes() {
+	code, responseBody := suite.getJson("/api/notifier-types")
+	assert.Equal(suite.T(), http.StatusOK, code)
+
+	types := responseBody["data"].([]interface{})
+	assert.NotEmpty(suite.T(), types)
+
+	var webhook map[string]interface{}
+	for _, notifierType := range types {
+		if notifierType.(map[string]interface{})["type"] == "webhook" {
+			webhook = notifierType.(map[string]interface{})
+		}
+	}
+	assert.NotNil(suite.T(), webhook)
+	assert.Equal(suite.T(), "Webhook", webhook["name"])
+	assert.NotEmpty(suite.T(), webhook["form"])
+}
+
+func (suite *MonHttpTestSuite) TestUpdateNotifierShouldReturnFieldErrorsForInvalidConfiguration() {
+	created := suite.createNotifier(map[string]interface{}{"type": "email", "name": "Mail"})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name": "Mail",
+		"data": map[string]interface{}{
+			"enabled": true,
+			"host":    "smtp.example.com",
+			"port":    0,
+			"from":    "monhttp",
+			"to":      "ops@example.com",
+			"color":   "red",
+		},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", fmt.Sprintf("/api/notifiers/%s", created["id"]), bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "invalid notifier configuration", responseBody["message"])
+	assert.Equal(suite.T(), []interface{}{
+		map[string]interface{}{"field": "color", "message": "is unknown"},
+		map[string]interface{}{"field": "port", "message": "is required"},
+		map[string]interface{}{"field": "from", "message": "'monhttp' is not a valid email address"},
+	}, responseBody["errors"])
+}
+
+func (suite *MonHttpTestSuite) TestSetupNotifiersShouldImportConfiguredNotifiersOnce() {
+	viper.Set("NOTIFIER_TELEGRAM_ENABLED", true)
+	viper.Set("NOTIFIER_TELEGRAM_CHANNEL", "@monhttp")
+	defer viper.Set("NOTIFIER_TELEGRAM_ENABLED", false)
+	defer viper.Set("NOTIFIER_TELEGRAM_CHANNEL", "")
+	defer viper.Set("NOTIFIERS_IMPORTED", false)
+
+	assert.Nil(suite.T(), service.SetupNotifiers(context.Background()))
+
+	// the flag is lost, e.g. because the configuration file is read-only
+	viper.Set("NOTIFIERS_IMPORTED", false)
+	assert.Nil(suite.T(), service.SetupNotifiers(context.Background()))
+
+	code, notifiers := suite.getJson("/api/notifiers")
+	assert.Equal(suite.T(), http.StatusOK, code)
+
+	count := 0
+	for _, notifier := range notifiers["data"].([]interface{}) {
+		if notifier.(map[string]interface{})["id"] == "telegram" {
+			count++
+		}
+	}
+	assert.Equal(suite.T(), 1, count)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", "/api/notifiers/telegram", nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+}
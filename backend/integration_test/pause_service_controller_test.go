@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) TestPauseServiceShouldReturnNotFoundForUnknownService() {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/"+uuid.New().String()+"/pause", nil)
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestPauseServicesShouldReturnErrorWithoutSelection() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"ids": []string{},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/pause", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "either 'ids' must not be empty or 'all' must be true", responseBody["message"])
+}
+
+func (suite *MonHttpTestSuite) TestResumeServicesShouldReturnFieldErrorForInvalidId() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"ids": []string{uuid.New().String(), "not-a-uuid"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/resume", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), []interface{}{
+		map[string]interface{}{"field": "ids[1]", "message": "'not-a-uuid' is not a valid id"},
+	}, responseBody["errors"])
+}
+
+func (suite *MonHttpTestSuite) TestPauseAndResumeServiceShouldToggleEnabled() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "MyService",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost",
+		"httpMethod":              "GET",
+		"requestTimeoutInSeconds": 60,
+		"expectedHttpStatusCode":  200,
+		"notifiers":               []string{"global"},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+	assert.Equal(suite.T(), true, createdService["enabled"])
+
+	serviceId := createdService["id"].(string)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", "/api/services/"+serviceId+"/pause", nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var pausedService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &pausedService))
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), false, pausedService["enabled"])
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", "/api/services/"+serviceId+"/check?persist=true", nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, _ = http.NewRequest("POST", "/api/services/"+serviceId+"/resume", nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var resumedService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &resumedService))
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), true, resumedService["enabled"])
+}
@@ -0,0 +1,152 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// quietSchedule returns a schedule that is active the whole day on every day except today.
+func quietSchedule() map[string]interface{} {
+	days := make([]string, 0)
+	for _, day := range []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"} {
+		if !strings.HasPrefix(strings.ToUpper(time.Now().UTC().Weekday().String()), day) {
+			days = append(days, day)
+		}
+	}
+	return map[string]interface{}{"timeZone": "UTC", "days": days, "startTime": "00:00", "endTime": "00:00"}
+}
+
+func (suite *MonHttpTestSuite) TestNotificationShouldBeHeldDuringQuietHours() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type":     "webhook",
+		"name":     "Quiet hook",
+		"schedule": quietSchedule(),
+		"data":     map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+	assert.Equal(suite.T(), "UTC", notifier["schedule"].(map[string]interface{})["timeZone"])
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Staging",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	path := fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"])
+	code, notifications := suite.getJson(path)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(1), notifications["totalCount"])
+
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	service.DeliverNotification(notification["id"].(string))
+
+	_, notifications = suite.getJson(path)
+	notification = notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(suite.T(), "HELD", notification["status"])
+	assert.Equal(suite.T(), float64(0), notification["attempts"])
+}
+
+func (suite *MonHttpTestSuite) TestCreateNotifierShouldReturnBadRequestForInvalidSchedule() {
+	for _, schedule := range []map[string]interface{}{
+		{"timeZone": "Mars/Olympus_Mons", "startTime": "08:00", "endTime": "20:00"},
+		{"timeZone": "Europe/Berlin", "startTime": "8 am", "endTime": "20:00"},
+		{"timeZone": "Europe/Berlin", "days": []string{"MONDAY"}, "startTime": "08:00", "endTime": "20:00"},
+		{"timeZone": "Europe/Berlin", "startTime": "08:00", "endTime": "20:00", "fallbackNotifierId": "unknown"},
+	} {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"type":     "email",
+			"name":     "Invalid schedule",
+			"schedule": schedule,
+			"data":     map[string]interface{}{"enabled": false},
+		})
+		assert.Nil(suite.T(), err)
+
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("POST", "/api/notifiers", bytes.NewBuffer(requestBody))
+		request.SetBasicAuth(user, password)
+		suite.router.ServeHTTP(recorder, request)
+
+		assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code, schedule)
+	}
+}
+
+func (suite *MonHttpTestSuite) TestFallbackNotificationShouldKeepDetails() {
+	fallback := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Fallback hook",
+		"data": map[string]interface{}{
+			"enabled":               true,
+			"url":                   "http://localhost:1/fallback",
+			"SERVICE_DOWN_TEMPLATE": "{{.Name}} failed {{.ConsecutiveFailures}} times since {{.DownSince}}",
+		},
+	})
+
+	schedule := quietSchedule()
+	schedule["fallbackNotifierId"] = fallback["id"]
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type":     "webhook",
+		"name":     "Quiet hook",
+		"schedule": schedule,
+		"data":     map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Routed",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+
+	_, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&notifierId=%s", notifier["id"]))
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	service.DeliverNotification(notification["id"].(string))
+
+	_, notifications = suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&notifierId=%s", fallback["id"]))
+	assert.Equal(suite.T(), float64(1), notifications["totalCount"])
+
+	routed := notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Regexp(suite.T(), `^Routed failed 1 times since \S+`, routed["payload"])
+}
@@ -0,0 +1,59 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) sendServiceRequest(method, url string, parentIds []string) (int, map[string]interface{}) {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "MyService",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost",
+		"httpMethod":              "GET",
+		"requestTimeoutInSeconds": 60,
+		"expectedHttpStatusCode":  200,
+		"notifiers":               []string{"global"},
+		"parentIds":               parentIds,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	return recorder.Code, responseBody
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnErrorForUnknownParent() {
+	code, responseBody := suite.sendServiceRequest("POST", "/api/services", []string{uuid.New().String()})
+
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Contains(suite.T(), responseBody["message"], "parent service does not exist")
+}
+
+func (suite *MonHttpTestSuite) TestUpdateServiceShouldReturnErrorForDependencyCycle() {
+	code, gateway := suite.sendServiceRequest("POST", "/api/services", []string{})
+	assert.Equal(suite.T(), http.StatusCreated, code)
+
+	gatewayId := gateway["id"].(string)
+
+	code, child := suite.sendServiceRequest("POST", "/api/services", []string{gatewayId})
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	assert.Equal(suite.T(), []interface{}{gatewayId}, child["parentIds"])
+
+	code, responseBody := suite.sendServiceRequest("PUT", "/api/services/"+gatewayId, []string{child["id"].(string)})
+
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Equal(suite.T(), "service dependencies must not contain a cycle", responseBody["message"])
+}
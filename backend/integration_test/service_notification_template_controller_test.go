@@ -0,0 +1,81 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldStoreNotificationTemplates() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "telegram",
+		"name": "Team A",
+		"data": map[string]interface{}{"enabled": true, "apiToken": "token", "channel": "4711"},
+	})
+
+	recorder := suite.postServiceWithNotificationTemplates(notifier["id"], "{{.Name}} is down: {{.Reason}}")
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	code, service := suite.getJson(fmt.Sprintf("/api/services/%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+
+	templates := service["notificationTemplates"].([]interface{})
+	assert.Equal(suite.T(), 1, len(templates))
+	assert.Equal(suite.T(), notifier["id"], templates[0].(map[string]interface{})["notifierId"])
+	assert.Equal(suite.T(), "{{.Name}} is down: {{.Reason}}", templates[0].(map[string]interface{})["downTemplate"])
+
+	recorder = httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/notifiers/%s", notifier["id"]), nil)
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	code, service = suite.getJson(fmt.Sprintf("/api/services/%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), 0, len(service["notificationTemplates"].([]interface{})))
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnBadRequestForInvalidNotificationTemplate() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "telegram",
+		"name": "Team A",
+		"data": map[string]interface{}{"enabled": true, "apiToken": "token", "channel": "4711"},
+	})
+
+	recorder := suite.postServiceWithNotificationTemplates(notifier["id"], "{{.Name")
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+
+	recorder = suite.postServiceWithNotificationTemplates(notifier["id"], "{{.Unknown}}")
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnBadRequestForNotificationTemplateOfUnknownNotifier() {
+	recorder := suite.postServiceWithNotificationTemplates("unknown", "{{.Name}} is down")
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *MonHttpTestSuite) postServiceWithNotificationTemplates(notifierId interface{}, downTemplate string) *httptest.ResponseRecorder {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Service with templates",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"requestTimeoutInSeconds": 1,
+		"notifiers":               []interface{}{notifierId},
+		"notificationTemplates": []interface{}{
+			map[string]interface{}{"notifierId": notifierId, "downTemplate": downTemplate},
+		},
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	return recorder
+}
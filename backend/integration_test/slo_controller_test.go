@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) TestFailingServiceShouldSendSingleFastBurnNotification() {
+	notifier := suite.createNotifier(map[string]interface{}{
+		"type": "webhook",
+		"name": "Hook",
+		"data": map[string]interface{}{"enabled": true, "url": "http://localhost:1/hook"},
+	})
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                        "Service with SLO",
+		"type":                        "HTTP",
+		"intervalInSeconds":           30,
+		"endpoint":                    "http://localhost:1",
+		"httpMethod":                  "GET",
+		"requestTimeoutInSeconds":     1,
+		"expectedHttpStatusCode":      200,
+		"enableNotifications":         true,
+		"notifyAfterNumberOfFailures": 1,
+		"notifiers":                   []interface{}{notifier["id"]},
+		"sloTarget":                   99.9,
+		"sloAlertsOnly":               true,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	suite.checkServiceAndPersist(createdService["id"])
+	suite.checkServiceAndPersist(createdService["id"])
+
+	code, service := suite.getJson(fmt.Sprintf("/api/services/%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), "FAST", service["sloBurnState"])
+
+	code, status := suite.getJson(fmt.Sprintf("/api/services/%s/slo", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), 99.9, status["target"])
+	assert.Equal(suite.T(), float64(30), status["windowInDays"])
+	assert.Equal(suite.T(), float64(0), status["uptime"])
+	assert.Equal(suite.T(), 14.4, status["fastBurnRateThreshold"])
+	assert.Greater(suite.T(), status["fastBurnRate"], 14.4)
+
+	code, notifications := suite.getJson(fmt.Sprintf("/api/notifications?page=0&pageSize=10&serviceId=%s", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), float64(1), notifications["totalCount"])
+
+	notification := notifications["data"].([]interface{})[0].(map[string]interface{})
+	assert.Contains(suite.T(), notification["reason"], "Fast error budget burn")
+}
+
+func (suite *MonHttpTestSuite) TestGetSloStatusShouldReturnNotFoundWithoutSlo() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Service without SLO",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"requestTimeoutInSeconds": 1,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	var createdService map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &createdService))
+
+	code, _ := suite.getJson(fmt.Sprintf("/api/services/%s/slo", createdService["id"]))
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func (suite *MonHttpTestSuite) TestCreateServiceShouldReturnBadRequestForSloTargetOf100() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                    "Invalid SLO",
+		"type":                    "HTTP",
+		"intervalInSeconds":       30,
+		"endpoint":                "http://localhost:1",
+		"requestTimeoutInSeconds": 1,
+		"sloTarget":               100,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
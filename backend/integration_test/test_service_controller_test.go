@@ -0,0 +1,74 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *MonHttpTestSuite) TestTestServiceShouldReturnProbeResult() {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(suite.T(), "secret", request.Header.Get("X-Token"))
+		fmt.Fprint(writer, "status: healthy")
+	}))
+	defer server.Close()
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                     "MyService",
+		"type":                     "HTTP",
+		"intervalInSeconds":        30,
+		"endpoint":                 server.URL,
+		"httpMethod":               "GET",
+		"requestTimeoutInSeconds":  5,
+		"httpHeaders":              "X-Token:secret",
+		"expectedHttpResponseBody": "status: (healthy|ok)",
+		"expectedHttpStatusCode":   200,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/test", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), false, responseBody["isFailure"])
+	assert.Equal(suite.T(), float64(200), responseBody["statusCode"])
+	assert.Equal(suite.T(), "status: healthy", responseBody["bodyExcerpt"])
+	assert.Len(suite.T(), responseBody["assertions"], 2)
+}
+
+func (suite *MonHttpTestSuite) TestTestServiceShouldReturnErrorsForInvalidDefinition() {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"name":                     "MyService",
+		"type":                     "HTTP",
+		"intervalInSeconds":        30,
+		"endpoint":                 "http://localhost",
+		"httpMethod":               "GET",
+		"requestTimeoutInSeconds":  5,
+		"httpHeaders":              "Authorization",
+		"expectedHttpResponseBody": "(unclosed",
+		"expectedHttpStatusCode":   200,
+	})
+	assert.Nil(suite.T(), err)
+
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/services/test", bytes.NewBuffer(requestBody))
+	request.SetBasicAuth(user, password)
+
+	suite.router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	assert.Nil(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), "invalid service definition", responseBody["message"])
+	assert.Len(suite.T(), responseBody["errors"], 2)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/koloo91/monhttp/controller"
 	"github.com/koloo91/monhttp/notifier"
@@ -8,6 +9,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"time"
+	// the docker image has no time zone database, it is needed by the formatTime template function
+	_ "time/tzdata"
 )
 
 func main() {
@@ -22,11 +25,7 @@ func main() {
 		log.Fatalf("Unable to load configuration: '%s'", err)
 	}
 
-	notificationSystem := notifier.NewNotificationSystem()
-	notificationSystem.SetupDefaultNotifier()
-	notificationSystem.Start()
-
-	service.SetNotificationSystem(notificationSystem)
+	service.SetNotificationSystem(notifier.NewNotificationSystem())
 
 	service.LoadUsers()
 
@@ -42,6 +41,10 @@ func main() {
 		if err != nil {
 			log.Fatalf("Unable to connect to database: '%s'", err)
 		}
+
+		if err := service.SetupNotifiers(context.Background()); err != nil {
+			log.Fatalf("Unable to setup notifiers: '%s'", err)
+		}
 	}
 
 	defer func() {
@@ -0,0 +1,2 @@
+alter table service
+    add tags varchar[] default '{}'::varchar[] not null;
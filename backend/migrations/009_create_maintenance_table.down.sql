@@ -0,0 +1,3 @@
+alter table "check" drop column is_maintenance;
+
+drop table maintenance;
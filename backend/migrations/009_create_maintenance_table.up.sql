@@ -0,0 +1,27 @@
+create table maintenance
+(
+    id          uuid                           not null,
+    name        varchar                        not null,
+    description varchar default ''             not null,
+    start_at    timestamptz                    not null,
+    end_at      timestamptz                    not null,
+    recurrence  varchar default 'NONE'         not null,
+    skip_checks bool    default false          not null,
+    service_ids varchar[] default '{}'::varchar[] not null,
+    tags        varchar[] default '{}'::varchar[] not null,
+    created_at  timestamptz                    not null,
+    updated_at  timestamptz                    not null
+);
+
+create unique index maintenance_id_uindex
+    on maintenance (id);
+
+create index maintenance_start_at_index
+    on maintenance (start_at);
+
+alter table maintenance
+    add constraint maintenance_pk
+        primary key (id);
+
+alter table "check"
+    add is_maintenance bool default false not null;
@@ -0,0 +1,2 @@
+alter table service
+    add enabled bool default true not null;
@@ -0,0 +1,3 @@
+alter table "check" drop column is_dependency_down;
+
+alter table service drop column parent_ids;
@@ -0,0 +1,5 @@
+alter table service
+    add parent_ids varchar[] default '{}'::varchar[] not null;
+
+alter table "check"
+    add is_dependency_down bool default false not null;
@@ -0,0 +1,16 @@
+create table notifier
+(
+    id         varchar                    not null,
+    type       varchar                    not null,
+    name       varchar                    not null,
+    data       jsonb default '{}'::jsonb not null,
+    created_at timestamptz                not null,
+    updated_at timestamptz                not null
+);
+
+create unique index notifier_id_uindex
+    on notifier (id);
+
+alter table notifier
+    add constraint notifier_pk
+        primary key (id);
@@ -0,0 +1,30 @@
+create table notification
+(
+    id                 uuid                      not null,
+    service_id         uuid                      not null,
+    service_name       varchar                   not null,
+    notifier_id        varchar                   not null,
+    is_up_notification bool                      not null,
+    reason             varchar     default ''    not null,
+    payload            varchar     default ''    not null,
+    status             varchar                   not null,
+    attempts           int         default 0     not null,
+    last_error         varchar     default ''    not null,
+    next_attempt_at    timestamptz               not null,
+    sent_at            timestamptz,
+    created_at         timestamptz               not null,
+    updated_at         timestamptz               not null
+);
+
+create unique index notification_id_uindex
+    on notification (id);
+
+create index notification_status_next_attempt_at_index
+    on notification (status, next_attempt_at);
+
+create index notification_created_at_index
+    on notification (created_at);
+
+alter table notification
+    add constraint notification_pk
+        primary key (id);
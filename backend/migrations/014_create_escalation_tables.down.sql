@@ -0,0 +1,4 @@
+alter table service
+    drop column escalation_policy_id;
+
+drop table escalation_policy;
@@ -0,0 +1,18 @@
+create table escalation_policy
+(
+    id         uuid                       not null,
+    name       varchar                    not null,
+    steps      jsonb default '[]'::jsonb not null,
+    created_at timestamptz                not null,
+    updated_at timestamptz                not null
+);
+
+create unique index escalation_policy_id_uindex
+    on escalation_policy (id);
+
+alter table escalation_policy
+    add constraint escalation_policy_pk
+        primary key (id);
+
+alter table service
+    add escalation_policy_id varchar default '' not null;
@@ -0,0 +1,6 @@
+alter table failure
+    drop column incident_id;
+
+drop table incident_note;
+
+drop table incident;
@@ -0,0 +1,64 @@
+create table incident
+(
+    id              uuid                  not null,
+    service_id      uuid                  not null
+        constraint incident_service_id_fk
+            references service
+            on delete cascade,
+    reason          varchar    default '' not null,
+    acknowledged_at timestamptz,
+    acknowledged_by varchar    default '' not null,
+    resolved_at     timestamptz,
+    created_at      timestamptz           not null,
+    updated_at      timestamptz           not null
+);
+
+create unique index incident_id_uindex
+    on incident (id);
+
+create unique index incident_open_service_id_uindex
+    on incident (service_id)
+    where resolved_at is null;
+
+create index incident_service_id_created_at_index
+    on incident (service_id, created_at desc);
+
+alter table incident
+    add constraint incident_pk
+        primary key (id);
+
+alter table incident
+    add escalation_policy_id varchar default '' not null,
+    add escalation_step      int     default 0  not null,
+    add next_escalation_at   timestamptz;
+
+create index incident_next_escalation_at_index
+    on incident (next_escalation_at);
+
+create table incident_note
+(
+    id          uuid                 not null,
+    incident_id uuid                 not null
+        constraint incident_note_incident_id_fk
+            references incident
+            on delete cascade,
+    text        varchar              not null,
+    author      varchar default ''   not null,
+    created_at  timestamptz          not null
+);
+
+create unique index incident_note_id_uindex
+    on incident_note (id);
+
+create index incident_note_incident_id_index
+    on incident_note (incident_id);
+
+alter table incident_note
+    add constraint incident_note_pk
+        primary key (id);
+
+alter table failure
+    add incident_id uuid;
+
+create index failure_incident_id_index
+    on failure (incident_id);
@@ -0,0 +1,17 @@
+drop index notification_notifier_id_status_index;
+
+delete
+from notification
+where is_digest = true;
+
+alter table notification
+    drop column digest_id;
+
+alter table notification
+    drop column is_digest;
+
+alter table notification
+    alter column service_id set not null;
+
+alter table notifier
+    drop column rate_limit;
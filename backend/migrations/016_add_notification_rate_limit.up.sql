@@ -0,0 +1,14 @@
+alter table notifier
+    add rate_limit int default 0 not null;
+
+alter table notification
+    alter column service_id drop not null;
+
+alter table notification
+    add is_digest bool default false not null;
+
+alter table notification
+    add digest_id uuid;
+
+create index notification_notifier_id_status_index
+    on notification (notifier_id, status);
@@ -0,0 +1,8 @@
+alter table service
+    drop column is_flapping;
+
+alter table service
+    drop column flapping_window;
+
+alter table service
+    drop column flapping_threshold;
@@ -0,0 +1,8 @@
+alter table service
+    add flapping_threshold int default 0 not null;
+
+alter table service
+    add flapping_window int default 0 not null;
+
+alter table service
+    add is_flapping bool default false not null;
@@ -0,0 +1,2 @@
+alter table service
+    drop column notification_templates;
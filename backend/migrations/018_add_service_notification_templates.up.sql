@@ -0,0 +1,2 @@
+alter table service
+    add notification_templates jsonb default '[]' not null;
@@ -0,0 +1,2 @@
+alter table notifier
+    drop column schedule;
@@ -0,0 +1,2 @@
+alter table notifier
+    add schedule jsonb;
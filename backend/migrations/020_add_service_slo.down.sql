@@ -0,0 +1,17 @@
+alter table service
+    drop column slo_burn_state;
+
+alter table service
+    drop column slo_alerts_only;
+
+alter table service
+    drop column slo_slow_burn_rate;
+
+alter table service
+    drop column slo_fast_burn_rate;
+
+alter table service
+    drop column slo_window_in_days;
+
+alter table service
+    drop column slo_target;
@@ -0,0 +1,17 @@
+alter table service
+    add slo_target double precision default 0 not null;
+
+alter table service
+    add slo_window_in_days int default 0 not null;
+
+alter table service
+    add slo_fast_burn_rate double precision default 0 not null;
+
+alter table service
+    add slo_slow_burn_rate double precision default 0 not null;
+
+alter table service
+    add slo_alerts_only bool default false not null;
+
+alter table service
+    add slo_burn_state varchar default '' not null;
@@ -0,0 +1,17 @@
+alter table notification
+    drop column is_degraded_notification;
+
+alter table service
+    drop column is_degraded;
+
+alter table service
+    drop column latency_deviation;
+
+alter table service
+    drop column latency_window;
+
+alter table service
+    drop column latency_percentile;
+
+alter table service
+    drop column latency_threshold_in_ms;
@@ -0,0 +1,17 @@
+alter table service
+    add latency_threshold_in_ms bigint default 0 not null;
+
+alter table service
+    add latency_percentile int default 0 not null;
+
+alter table service
+    add latency_window int default 0 not null;
+
+alter table service
+    add latency_deviation double precision default 0 not null;
+
+alter table service
+    add is_degraded bool default false not null;
+
+alter table notification
+    add is_degraded_notification bool default false not null;
@@ -0,0 +1,2 @@
+alter table notification
+    drop column output;
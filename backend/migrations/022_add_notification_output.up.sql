@@ -0,0 +1,2 @@
+alter table notification
+    add output varchar default '' not null;
@@ -0,0 +1,2 @@
+alter table notification
+    add details jsonb;
@@ -4,3 +4,9 @@ type ApiErrorVo struct {
 	Message string        `json:"message"`
 	Errors  []interface{} `json:"errors"`
 }
+
+// FieldErrorVo is a validation error of a single field of the request body.
+type FieldErrorVo struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
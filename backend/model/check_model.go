@@ -6,19 +6,25 @@ import (
 )
 
 type Check struct {
-	Id          string
-	ServiceId   string
-	LatencyInMs int64
-	IsFailure   bool
-	CreatedAt   time.Time
+	Id               string
+	ServiceId        string
+	LatencyInMs      int64
+	IsFailure        bool
+	IsMaintenance    bool
+	IsDependencyDown bool
+	// StatusCode is the http status code of the probe. It is only used for notifications and not stored.
+	StatusCode int
+	CreatedAt  time.Time
 }
 
 type CheckVo struct {
-	Id          string    `json:"id"`
-	ServiceId   string    `json:"serviceId"`
-	LatencyInMs int64     `json:"latencyInMs"`
-	IsFailure   bool      `json:"isFailure"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id               string    `json:"id"`
+	ServiceId        string    `json:"serviceId"`
+	LatencyInMs      int64     `json:"latencyInMs"`
+	IsFailure        bool      `json:"isFailure"`
+	IsMaintenance    bool      `json:"isMaintenance"`
+	IsDependencyDown bool      `json:"isDependencyDown"`
+	CreatedAt        time.Time `json:"createdAt"`
 }
 
 func NewCheck(serviceId string, latency int64, isFailure bool) *Check {
@@ -33,11 +39,13 @@ func NewCheck(serviceId string, latency int64, isFailure bool) *Check {
 
 func MapCheckEntityToVo(entity Check) CheckVo {
 	return CheckVo{
-		Id:          entity.Id,
-		ServiceId:   entity.ServiceId,
-		LatencyInMs: entity.LatencyInMs,
-		IsFailure:   entity.IsFailure,
-		CreatedAt:   entity.CreatedAt,
+		Id:               entity.Id,
+		ServiceId:        entity.ServiceId,
+		LatencyInMs:      entity.LatencyInMs,
+		IsFailure:        entity.IsFailure,
+		IsMaintenance:    entity.IsMaintenance,
+		IsDependencyDown: entity.IsDependencyDown,
+		CreatedAt:        entity.CreatedAt,
 	}
 }
 
@@ -50,3 +58,14 @@ func MapCheckEntitiesToVos(entities []Check) []CheckVo {
 
 	return result
 }
+
+// CountStateChanges returns how often the checks switch between up and down.
+func CountStateChanges(checks []Check) int {
+	stateChanges := 0
+	for i := 1; i < len(checks); i++ {
+		if checks[i].IsFailure != checks[i-1].IsFailure {
+			stateChanges++
+		}
+	}
+	return stateChanges
+}
@@ -0,0 +1,23 @@
+package model
+
+type CheckResultVo struct {
+	Check     *CheckVo   `json:"check"`
+	Failure   *FailureVo `json:"failure"`
+	Persisted bool       `json:"persisted"`
+}
+
+func MapCheckResultToVo(check *Check, failure *Failure, persisted bool) CheckResultVo {
+	result := CheckResultVo{Persisted: persisted}
+
+	if check != nil {
+		checkVo := MapCheckEntityToVo(*check)
+		result.Check = &checkVo
+	}
+
+	if failure != nil {
+		failureVo := MapFailureEntityToVo(*failure)
+		result.Failure = &failureVo
+	}
+
+	return result
+}
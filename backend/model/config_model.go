@@ -1,11 +1,18 @@
 package model
 
 type Config struct {
-	ServerPort int `mapstructure:"SERVER_PORT"`
+	ServerPort int    `mapstructure:"SERVER_PORT"`
+	PublicUrl  string `mapstructure:"PUBLIC_URL"`
 
 	SchedulerEnabled         bool `mapstructure:"SCHEDULER_ENABLED"`
 	SchedulerNumberOfWorkers int  `mapstructure:"SCHEDULER_NUMBER_OF_WORKERS"`
 
+	NotificationMaxAttempts             int `mapstructure:"NOTIFICATION_MAX_ATTEMPTS"`
+	NotificationRateLimit               int `mapstructure:"NOTIFICATION_RATE_LIMIT"`
+	NotificationDigestIntervalInSeconds int `mapstructure:"NOTIFICATION_DIGEST_INTERVAL_IN_SECONDS"`
+
+	ExecNotifierEnabled bool `mapstructure:"EXEC_NOTIFIER_ENABLED"`
+
 	Host         string `mapstructure:"DATABASE_HOST"`
 	Port         int    `mapstructure:"DATABASE_PORT"`
 	User         string `mapstructure:"DATABASE_USER"`
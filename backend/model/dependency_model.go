@@ -0,0 +1,29 @@
+package model
+
+type ServiceDependency struct {
+	ServiceId string
+	Name      string
+	ParentIds []string
+}
+
+type ServiceDependencyVo struct {
+	ServiceId string   `json:"serviceId"`
+	Name      string   `json:"name"`
+	ParentIds []string `json:"parentIds"`
+}
+
+func MapServiceDependencyEntityToVo(entity ServiceDependency) ServiceDependencyVo {
+	return ServiceDependencyVo{
+		ServiceId: entity.ServiceId,
+		Name:      entity.Name,
+		ParentIds: entity.ParentIds,
+	}
+}
+
+func MapServiceDependencyEntitiesToVos(entities []ServiceDependency) []ServiceDependencyVo {
+	result := make([]ServiceDependencyVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapServiceDependencyEntityToVo(entity))
+	}
+	return result
+}
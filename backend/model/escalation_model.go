@@ -0,0 +1,97 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// EscalationStep notifies its notifiers and waits DelayInMinutes for an acknowledgement before the next step of
+// the policy is notified.
+type EscalationStep struct {
+	NotifierIds    []string `json:"notifierIds"`
+	DelayInMinutes int      `json:"delayInMinutes"`
+}
+
+type EscalationPolicy struct {
+	Id        string
+	Name      string
+	Steps     []EscalationStep
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type EscalationStepVo struct {
+	NotifierIds    []string `json:"notifierIds" binding:"required,min=1"`
+	DelayInMinutes int      `json:"delayInMinutes" binding:"min=0,max=10080"`
+}
+
+type EscalationPolicyVo struct {
+	Id        string             `json:"id"`
+	Name      string             `json:"name" binding:"required"`
+	Steps     []EscalationStepVo `json:"steps" binding:"required,min=1,dive"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// NotifierIds returns the notifiers of all steps up to the given one.
+func (p EscalationPolicy) NotifierIds(step int) []string {
+	result := make([]string, 0)
+	for i := 0; i <= step && i < len(p.Steps); i++ {
+		result = append(result, p.Steps[i].NotifierIds...)
+	}
+	return result
+}
+
+// NextStepAt returns when the step after the given one is due or nil if the given step is the last one.
+func (p EscalationPolicy) NextStepAt(step int, notifiedAt time.Time) *time.Time {
+	if step+1 >= len(p.Steps) {
+		return nil
+	}
+
+	nextStepAt := notifiedAt.Add(time.Duration(p.Steps[step].DelayInMinutes) * time.Minute)
+	return &nextStepAt
+}
+
+func MapEscalationPolicyVoToEntity(vo EscalationPolicyVo) EscalationPolicy {
+	steps := make([]EscalationStep, 0, len(vo.Steps))
+	for _, step := range vo.Steps {
+		steps = append(steps, EscalationStep{
+			NotifierIds:    step.NotifierIds,
+			DelayInMinutes: step.DelayInMinutes,
+		})
+	}
+
+	return EscalationPolicy{
+		Id:        uuid.New().String(),
+		Name:      vo.Name,
+		Steps:     steps,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func MapEscalationPolicyEntityToVo(entity EscalationPolicy) EscalationPolicyVo {
+	steps := make([]EscalationStepVo, 0, len(entity.Steps))
+	for _, step := range entity.Steps {
+		steps = append(steps, EscalationStepVo{
+			NotifierIds:    step.NotifierIds,
+			DelayInMinutes: step.DelayInMinutes,
+		})
+	}
+
+	return EscalationPolicyVo{
+		Id:        entity.Id,
+		Name:      entity.Name,
+		Steps:     steps,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func MapEscalationPolicyEntitiesToVos(entities []EscalationPolicy) []EscalationPolicyVo {
+	result := make([]EscalationPolicyVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapEscalationPolicyEntityToVo(entity))
+	}
+	return result
+}
@@ -6,17 +6,19 @@ import (
 )
 
 type Failure struct {
-	Id        string
-	ServiceId string
-	Reason    string
-	CreatedAt time.Time
+	Id         string
+	ServiceId  string
+	IncidentId string
+	Reason     string
+	CreatedAt  time.Time
 }
 
 type FailureVo struct {
-	Id        string    `json:"id"`
-	ServiceId string    `json:"serviceId"`
-	Reason    string    `json:"reason"`
-	CreatedAt time.Time `json:"createdAt"`
+	Id         string    `json:"id"`
+	ServiceId  string    `json:"serviceId"`
+	IncidentId string    `json:"incidentId"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
 func NewFailure(serviceId string, reason string) *Failure {
@@ -30,10 +32,11 @@ func NewFailure(serviceId string, reason string) *Failure {
 
 func MapFailureEntityToVo(entity Failure) FailureVo {
 	return FailureVo{
-		Id:        entity.Id,
-		ServiceId: entity.ServiceId,
-		Reason:    entity.Reason,
-		CreatedAt: entity.CreatedAt,
+		Id:         entity.Id,
+		ServiceId:  entity.ServiceId,
+		IncidentId: entity.IncidentId,
+		Reason:     entity.Reason,
+		CreatedAt:  entity.CreatedAt,
 	}
 }
 
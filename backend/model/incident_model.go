@@ -0,0 +1,163 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	IncidentStatusOpen     = "OPEN"
+	IncidentStatusResolved = "RESOLVED"
+)
+
+// Incident is one down period of a service. It is opened by the first failure and resolved by the next successful
+// check. The failures in between reference the incident.
+type Incident struct {
+	Id        string
+	ServiceId string
+	Reason    string
+	// EscalationPolicyId is set once the down notification was sent to the first step of the policy
+	EscalationPolicyId string
+	// EscalationStep is the index of the last step whose notifiers were notified
+	EscalationStep int
+	// NextEscalationAt is nil if the last step was reached or the incident was acknowledged
+	NextEscalationAt *time.Time
+	AcknowledgedAt   *time.Time
+	AcknowledgedBy   string
+	ResolvedAt       *time.Time
+	FailureCount     int
+	Notes            []IncidentNote
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type IncidentVo struct {
+	Id                 string           `json:"id"`
+	ServiceId          string           `json:"serviceId"`
+	Reason             string           `json:"reason"`
+	EscalationPolicyId string           `json:"escalationPolicyId"`
+	EscalationStep     int              `json:"escalationStep"`
+	NextEscalationAt   *time.Time       `json:"nextEscalationAt"`
+	AcknowledgedAt     *time.Time       `json:"acknowledgedAt"`
+	AcknowledgedBy     string           `json:"acknowledgedBy"`
+	ResolvedAt         *time.Time       `json:"resolvedAt"`
+	FailureCount       int              `json:"failureCount"`
+	Notes              []IncidentNoteVo `json:"notes"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt"`
+}
+
+type IncidentNote struct {
+	Id         string
+	IncidentId string
+	Text       string
+	Author     string
+	CreatedAt  time.Time
+}
+
+type IncidentNoteVo struct {
+	Id         string    `json:"id"`
+	IncidentId string    `json:"incidentId"`
+	Text       string    `json:"text" binding:"required"`
+	Author     string    `json:"author"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// IncidentStatistics are the mean times to acknowledge and to recover of the incidents opened in a period.
+// Open incidents are not part of the mean time to recover.
+type IncidentStatistics struct {
+	Count                 int
+	ResolvedCount         int
+	MeanTimeToAcknowledge time.Duration
+	MeanTimeToRecover     time.Duration
+}
+
+type IncidentStatisticsVo struct {
+	Count                          int     `json:"count"`
+	ResolvedCount                  int     `json:"resolvedCount"`
+	MeanTimeToAcknowledgeInSeconds float64 `json:"meanTimeToAcknowledgeInSeconds"`
+	MeanTimeToRecoverInSeconds     float64 `json:"meanTimeToRecoverInSeconds"`
+}
+
+func NewIncident(serviceId, reason string) Incident {
+	now := time.Now()
+	return Incident{
+		Id:        uuid.New().String(),
+		ServiceId: serviceId,
+		Reason:    reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (i Incident) IsAcknowledged() bool {
+	return i.AcknowledgedAt != nil
+}
+
+// IsEscalated reports whether the down notification of the incident was sent according to an escalation policy.
+func (i Incident) IsEscalated() bool {
+	return len(i.EscalationPolicyId) > 0
+}
+
+func MapIncidentEntityToVo(entity Incident) IncidentVo {
+	return IncidentVo{
+		Id:                 entity.Id,
+		ServiceId:          entity.ServiceId,
+		Reason:             entity.Reason,
+		EscalationPolicyId: entity.EscalationPolicyId,
+		EscalationStep:     entity.EscalationStep,
+		NextEscalationAt:   entity.NextEscalationAt,
+		AcknowledgedAt:     entity.AcknowledgedAt,
+		AcknowledgedBy:     entity.AcknowledgedBy,
+		ResolvedAt:         entity.ResolvedAt,
+		FailureCount:       entity.FailureCount,
+		Notes:              MapIncidentNoteEntitiesToVos(entity.Notes),
+		CreatedAt:          entity.CreatedAt,
+		UpdatedAt:          entity.UpdatedAt,
+	}
+}
+
+func MapIncidentEntitiesToVos(entities []Incident) []IncidentVo {
+	result := make([]IncidentVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapIncidentEntityToVo(entity))
+	}
+	return result
+}
+
+func MapIncidentNoteVoToEntity(vo IncidentNoteVo, incidentId, author string) IncidentNote {
+	return IncidentNote{
+		Id:         uuid.New().String(),
+		IncidentId: incidentId,
+		Text:       vo.Text,
+		Author:     author,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func MapIncidentNoteEntityToVo(entity IncidentNote) IncidentNoteVo {
+	return IncidentNoteVo{
+		Id:         entity.Id,
+		IncidentId: entity.IncidentId,
+		Text:       entity.Text,
+		Author:     entity.Author,
+		CreatedAt:  entity.CreatedAt,
+	}
+}
+
+func MapIncidentNoteEntitiesToVos(entities []IncidentNote) []IncidentNoteVo {
+	result := make([]IncidentNoteVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapIncidentNoteEntityToVo(entity))
+	}
+	return result
+}
+
+func MapIncidentStatisticsToVo(statistics IncidentStatistics) IncidentStatisticsVo {
+	return IncidentStatisticsVo{
+		Count:                          statistics.Count,
+		ResolvedCount:                  statistics.ResolvedCount,
+		MeanTimeToAcknowledgeInSeconds: statistics.MeanTimeToAcknowledge.Seconds(),
+		MeanTimeToRecoverInSeconds:     statistics.MeanTimeToRecover.Seconds(),
+	}
+}
@@ -1,5 +1,7 @@
 package model
 
 type IsOnlineVo struct {
-	Online bool `json:"online"`
+	Online   bool `json:"online"`
+	Flapping bool `json:"flapping"`
+	Degraded bool `json:"degraded"`
 }
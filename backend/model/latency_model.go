@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultLatencyWindow = 10
+
+	// LatencyBaselinePeriod is the period before the latency window the baseline is computed from
+	LatencyBaselinePeriod = 7 * 24 * time.Hour
+	// MinLatencyBaselineChecks is the number of checks required before the baseline is used
+	MinLatencyBaselineChecks = 30
+	// minLatencyStdDevRatio prevents services with a nearly constant latency from being degraded by a few
+	// milliseconds
+	minLatencyStdDevRatio = 0.05
+)
+
+// LatencyBaseline is the mean and the standard deviation of the latency of the successful checks of a service.
+type LatencyBaseline struct {
+	Mean   float64
+	StdDev float64
+	Count  int
+}
+
+// Limit returns the latency that is deviation standard deviations above the mean, but at least 5% of the mean per
+// standard deviation. It returns false if the baseline has less than MinLatencyBaselineChecks checks.
+func (b LatencyBaseline) Limit(deviation float64) (float64, bool) {
+	if b.Count < MinLatencyBaselineChecks {
+		return 0, false
+	}
+	return b.Mean + deviation*math.Max(b.StdDev, b.Mean*minLatencyStdDevRatio), true
+}
+
+// LatencyAlertsEnabled returns true if the service has a fixed latency threshold or a baseline deviation.
+func (s Service) LatencyAlertsEnabled() bool {
+	return s.LatencyThresholdInMs > 0 || s.LatencyDeviation > 0
+}
+
+// LatencyCheckWindow returns the number of checks the latency is aggregated over, 10 if not set.
+func (s Service) LatencyCheckWindow() int {
+	if s.LatencyWindow == 0 {
+		return DefaultLatencyWindow
+	}
+	return s.LatencyWindow
+}
+
+// LatencyAggregationName returns the name of the aggregation of the service, e.g. p95 or average.
+func (s Service) LatencyAggregationName() string {
+	if s.LatencyPercentile == 0 {
+		return "average"
+	}
+	return fmt.Sprintf("p%d", s.LatencyPercentile)
+}
+
+// AggregateLatency returns the nearest rank percentile of the latencies or their average if percentile is 0.
+func AggregateLatency(latencies []int64, percentile int) float64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	if percentile == 0 {
+		var sum int64
+		for _, latency := range latencies {
+			sum += latency
+		}
+		return float64(sum) / float64(len(latencies))
+	}
+
+	sorted := make([]int64, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1])
+}
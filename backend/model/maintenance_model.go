@@ -0,0 +1,132 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	MaintenanceRecurrenceNone   = "NONE"
+	MaintenanceRecurrenceDaily  = "DAILY"
+	MaintenanceRecurrenceWeekly = "WEEKLY"
+)
+
+type MaintenanceRecurrence string
+
+type Maintenance struct {
+	Id          string
+	Name        string
+	Description string
+	StartAt     time.Time
+	EndAt       time.Time
+	Recurrence  MaintenanceRecurrence
+	SkipChecks  bool
+	ServiceIds  []string
+	Tags        []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type MaintenanceVo struct {
+	Id          string                `json:"id"`
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	StartAt     time.Time             `json:"startAt" binding:"required"`
+	EndAt       time.Time             `json:"endAt" binding:"required,gtfield=StartAt"`
+	Recurrence  MaintenanceRecurrence `json:"recurrence" binding:"omitempty,oneof=NONE DAILY WEEKLY"`
+	SkipChecks  bool                  `json:"skipChecks"`
+	ServiceIds  []string              `json:"serviceIds"`
+	Tags        []string              `json:"tags"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+}
+
+// Period returns the length of one recurrence cycle or zero for one-off windows.
+func (m Maintenance) Period() time.Duration {
+	switch m.Recurrence {
+	case MaintenanceRecurrenceDaily:
+		return 24 * time.Hour
+	case MaintenanceRecurrenceWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// IsActiveAt reports whether t lies inside the window or, for recurring windows, inside one of its repetitions.
+func (m Maintenance) IsActiveAt(t time.Time) bool {
+	if t.Before(m.StartAt) {
+		return false
+	}
+
+	period := m.Period()
+	if period == 0 {
+		return t.Before(m.EndAt)
+	}
+
+	offset := t.Sub(m.StartAt) % period
+	return offset < m.EndAt.Sub(m.StartAt)
+}
+
+// AppliesTo reports whether the window is attached to the service directly or through one of its tags.
+func (m Maintenance) AppliesTo(service Service) bool {
+	for _, serviceId := range m.ServiceIds {
+		if serviceId == service.Id {
+			return true
+		}
+	}
+
+	for _, tag := range m.Tags {
+		for _, serviceTag := range service.Tags {
+			if tag == serviceTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func MapMaintenanceVoToEntity(vo MaintenanceVo) Maintenance {
+	recurrence := vo.Recurrence
+	if len(recurrence) == 0 {
+		recurrence = MaintenanceRecurrenceNone
+	}
+
+	return Maintenance{
+		Id:          uuid.New().String(),
+		Name:        vo.Name,
+		Description: vo.Description,
+		StartAt:     vo.StartAt,
+		EndAt:       vo.EndAt,
+		Recurrence:  recurrence,
+		SkipChecks:  vo.SkipChecks,
+		ServiceIds:  mapNilSliceToEmpty(vo.ServiceIds),
+		Tags:        mapNilSliceToEmpty(vo.Tags),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func MapMaintenanceEntityToVo(entity Maintenance) MaintenanceVo {
+	return MaintenanceVo{
+		Id:          entity.Id,
+		Name:        entity.Name,
+		Description: entity.Description,
+		StartAt:     entity.StartAt,
+		EndAt:       entity.EndAt,
+		Recurrence:  entity.Recurrence,
+		SkipChecks:  entity.SkipChecks,
+		ServiceIds:  entity.ServiceIds,
+		Tags:        entity.Tags,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+func MapMaintenanceEntitiesToVos(entities []Maintenance) []MaintenanceVo {
+	result := make([]MaintenanceVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapMaintenanceEntityToVo(entity))
+	}
+	return result
+}
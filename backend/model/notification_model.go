@@ -0,0 +1,136 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "PENDING"
+	NotificationStatusSent    NotificationStatus = "SENT"
+	// NotificationStatusFailed is final, the delivery is not retried anymore
+	NotificationStatusFailed NotificationStatus = "FAILED"
+	// NotificationStatusSkipped is set for pending notifications that are replaced by a newer notification of the
+	// same service and notifier, e.g. a down notification that is still retried when the service is up again
+	NotificationStatusSkipped NotificationStatus = "SKIPPED"
+	// NotificationStatusCoalesced is set for notifications that exceeded a rate limit. They are sent as part of a
+	// digest notification, referenced by DigestId
+	NotificationStatusCoalesced NotificationStatus = "COALESCED"
+	// NotificationStatusHeld is set for notifications queued during the quiet hours of their notifier. They are sent
+	// as a digest notification once the notifier is active again
+	NotificationStatusHeld NotificationStatus = "HELD"
+)
+
+// Notification is the delivery of an up or down notification of a service with one notifier. Digest notifications
+// summarize coalesced or held notifications and have no service.
+type Notification struct {
+	Id               string
+	ServiceId        string
+	ServiceName      string
+	NotifierId       string
+	IsUpNotification bool
+	// IsDegradedNotification is set for notifications about the latency of a service: down notifications when it is
+	// degraded, up notifications when it is back to normal
+	IsDegradedNotification bool
+	Reason                 string
+	// Details the payload was rendered with
+	Details   NotificationDetails
+	Payload   string
+	Status    NotificationStatus
+	IsDigest  bool
+	DigestId  string
+	Attempts  int
+	LastError string
+	// Output of the last delivery attempt, e.g. of a command
+	Output        string
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type NotificationVo struct {
+	Id                     string             `json:"id"`
+	ServiceId              string             `json:"serviceId"`
+	ServiceName            string             `json:"serviceName"`
+	NotifierId             string             `json:"notifierId"`
+	IsUpNotification       bool               `json:"isUpNotification"`
+	IsDegradedNotification bool               `json:"isDegradedNotification"`
+	Reason                 string             `json:"reason"`
+	Payload                string             `json:"payload"`
+	Status                 NotificationStatus `json:"status"`
+	IsDigest               bool               `json:"isDigest"`
+	DigestId               string             `json:"digestId"`
+	Attempts               int                `json:"attempts"`
+	LastError              string             `json:"lastError"`
+	Output                 string             `json:"output"`
+	NextAttemptAt          time.Time          `json:"nextAttemptAt"`
+	SentAt                 *time.Time         `json:"sentAt"`
+	CreatedAt              time.Time          `json:"createdAt"`
+	UpdatedAt              time.Time          `json:"updatedAt"`
+}
+
+func NewNotification(service Service, notifierId string, isUpNotification bool, details NotificationDetails) Notification {
+	now := time.Now()
+	return Notification{
+		Id:                     uuid.New().String(),
+		ServiceId:              service.Id,
+		ServiceName:            service.Name,
+		NotifierId:             notifierId,
+		IsUpNotification:       isUpNotification,
+		IsDegradedNotification: details.IsDegraded,
+		Reason:                 details.Reason,
+		Details:                details,
+		Status:                 NotificationStatusPending,
+		NextAttemptAt:          now,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+}
+
+func NewDigestNotification(notifierId string) Notification {
+	now := time.Now()
+	return Notification{
+		Id:            uuid.New().String(),
+		ServiceName:   "monhttp",
+		NotifierId:    notifierId,
+		Status:        NotificationStatusPending,
+		IsDigest:      true,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func MapNotificationEntityToVo(entity Notification) NotificationVo {
+	return NotificationVo{
+		Id:                     entity.Id,
+		ServiceId:              entity.ServiceId,
+		ServiceName:            entity.ServiceName,
+		NotifierId:             entity.NotifierId,
+		IsUpNotification:       entity.IsUpNotification,
+		IsDegradedNotification: entity.IsDegradedNotification,
+		Reason:                 entity.Reason,
+		Payload:                entity.Payload,
+		Status:                 entity.Status,
+		IsDigest:               entity.IsDigest,
+		DigestId:               entity.DigestId,
+		Attempts:               entity.Attempts,
+		LastError:              entity.LastError,
+		Output:                 entity.Output,
+		NextAttemptAt:          entity.NextAttemptAt,
+		SentAt:                 entity.SentAt,
+		CreatedAt:              entity.CreatedAt,
+		UpdatedAt:              entity.UpdatedAt,
+	}
+}
+
+func MapNotificationEntitiesToVos(entities []Notification) []NotificationVo {
+	result := make([]NotificationVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, MapNotificationEntityToVo(entity))
+	}
+	return result
+}
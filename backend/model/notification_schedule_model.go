@@ -0,0 +1,139 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var scheduleDays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// NotificationSchedule defines the active hours of a notifier. Outside of them the notifier is in quiet hours and
+// only notifications of services with one of the CriticalTags are sent. Other notifications are routed to the
+// FallbackNotifierId or held and sent as a summary once the notifier is active again.
+type NotificationSchedule struct {
+	TimeZone string `json:"timeZone"`
+	// Days the active hours start on, e.g. MON. All days if empty
+	Days []string `json:"days"`
+	// StartTime and EndTime of the active hours like 08:00. An end before the start ends on the next day. Equal
+	// times are active the whole day
+	StartTime          string   `json:"startTime"`
+	EndTime            string   `json:"endTime"`
+	CriticalTags       []string `json:"criticalTags"`
+	FallbackNotifierId string   `json:"fallbackNotifierId"`
+}
+
+type NotificationScheduleVo struct {
+	TimeZone           string   `json:"timeZone" binding:"required"`
+	Days               []string `json:"days" binding:"dive,oneof=MON TUE WED THU FRI SAT SUN"`
+	StartTime          string   `json:"startTime" binding:"required"`
+	EndTime            string   `json:"endTime" binding:"required"`
+	CriticalTags       []string `json:"criticalTags"`
+	FallbackNotifierId string   `json:"fallbackNotifierId"`
+}
+
+// Validate returns an error if the time zone or the times of the schedule can not be parsed.
+func (s NotificationSchedule) Validate() error {
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return err
+	}
+	if _, err := parseClock(s.StartTime); err != nil {
+		return err
+	}
+	if _, err := parseClock(s.EndTime); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if the given time is within the active hours. Invalid schedules are always active.
+func (s NotificationSchedule) IsActive(t time.Time) bool {
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return true
+	}
+	start, err := parseClock(s.StartTime)
+	if err != nil {
+		return true
+	}
+	end, err := parseClock(s.EndTime)
+	if err != nil {
+		return true
+	}
+
+	t = t.In(location)
+	minute := t.Hour()*60 + t.Minute()
+
+	switch {
+	case start == end:
+		return s.isActiveDay(t.Weekday())
+	case start < end:
+		return s.isActiveDay(t.Weekday()) && minute >= start && minute < end
+	default:
+		if minute >= start {
+			return s.isActiveDay(t.Weekday())
+		}
+		return minute < end && s.isActiveDay(t.AddDate(0, 0, -1).Weekday())
+	}
+}
+
+// IsCritical returns true if the service has one of the critical tags of the schedule.
+func (s NotificationSchedule) IsCritical(service Service) bool {
+	for _, criticalTag := range s.CriticalTags {
+		for _, tag := range service.Tags {
+			if strings.EqualFold(tag, criticalTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s NotificationSchedule) isActiveDay(weekday time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, day := range s.Days {
+		if strings.EqualFold(day, scheduleDays[weekday]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock returns the minutes since midnight of a time like 08:30.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', expected a time like 08:30", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func MapNotificationScheduleVoToEntity(vo *NotificationScheduleVo) *NotificationSchedule {
+	if vo == nil {
+		return nil
+	}
+	return &NotificationSchedule{
+		TimeZone:           vo.TimeZone,
+		Days:               vo.Days,
+		StartTime:          vo.StartTime,
+		EndTime:            vo.EndTime,
+		CriticalTags:       vo.CriticalTags,
+		FallbackNotifierId: vo.FallbackNotifierId,
+	}
+}
+
+func MapNotificationScheduleEntityToVo(entity *NotificationSchedule) *NotificationScheduleVo {
+	if entity == nil {
+		return nil
+	}
+	return &NotificationScheduleVo{
+		TimeZone:           entity.TimeZone,
+		Days:               entity.Days,
+		StartTime:          entity.StartTime,
+		EndTime:            entity.EndTime,
+		CriticalTags:       entity.CriticalTags,
+		FallbackNotifierId: entity.FallbackNotifierId,
+	}
+}
@@ -1,26 +1,105 @@
 package model
 
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
 type Notifier struct {
-	Id      string
-	Name    string
-	Enabled bool
-	Data    map[string]interface{}
-	Form    []NotificationForm
+	Id        string
+	Type      string
+	Name      string
+	Enabled   bool
+	RateLimit int
+	Schedule  *NotificationSchedule
+	Data      map[string]interface{}
+	Form      []NotificationForm
+}
+
+// SetInstance turns the notifier of a type into a persisted instance. The type based id is kept as Type.
+func (n *Notifier) SetInstance(id, name string) {
+	n.Type = n.Id
+	n.Id = id
+	n.Name = name
+}
+
+// SetRateLimit sets the number of notifications per minute. Zero disables the limit.
+func (n *Notifier) SetRateLimit(rateLimit int) {
+	n.RateLimit = rateLimit
+}
+
+func (n *Notifier) GetRateLimit() int {
+	return n.RateLimit
+}
+
+// SetSchedule sets the active hours of the notifier. Nil means always active.
+func (n *Notifier) SetSchedule(schedule *NotificationSchedule) {
+	n.Schedule = schedule
+}
+
+func (n *Notifier) GetSchedule() *NotificationSchedule {
+	return n.Schedule
 }
 
+func (n *Notifier) GetType() string {
+	if len(n.Type) == 0 {
+		return n.Id
+	}
+	return n.Type
+}
+
+// NotifierInstance is a named configuration of a notifier type. Data holds the values of the form of the type.
+type NotifierInstance struct {
+	Id        string
+	Type      string
+	Name      string
+	RateLimit int
+	Schedule  *NotificationSchedule
+	Data      map[string]interface{}
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type NotifierInstanceVo struct {
+	Type      string                  `json:"type"`
+	Name      string                  `json:"name" binding:"required"`
+	RateLimit int                     `json:"rateLimit" binding:"min=0"`
+	Schedule  *NotificationScheduleVo `json:"schedule"`
+	Data      map[string]interface{}  `json:"data"`
+}
+
+// Formats of form values that are validated beyond their type.
+const (
+	FormFormatEmail     = "email"     // an email address
+	FormFormatEmailList = "emailList" // comma separated email addresses
+	FormFormatPort      = "port"      // a tcp or udp port
+	FormFormatTemplate  = "template"  // a notification template
+)
+
 type NotificationForm struct {
 	Type            string // the html input type (text, password, email)
 	Title           string // include a title for ease of use
 	FormControlName string
 	Placeholder     string // add a placeholder for the input
 	Required        bool   // require this input on the html form
+	Format          string // validate the value with one of the FormFormat* formats
 }
 
 type NotifierVo struct {
-	Id   string                 `json:"id"`
-	Name string                 `json:"name"`
-	Data map[string]interface{} `json:"data"`
-	Form []NotificationFormVo   `json:"form"`
+	Id        string                  `json:"id"`
+	Type      string                  `json:"type"`
+	Name      string                  `json:"name"`
+	RateLimit int                     `json:"rateLimit"`
+	Schedule  *NotificationScheduleVo `json:"schedule"`
+	Data      map[string]interface{}  `json:"data"`
+	Form      []NotificationFormVo    `json:"form"`
+}
+
+// NotifierTypeVo describes a notifier type. The ui renders the form of a new notifier from it.
+type NotifierTypeVo struct {
+	Type string               `json:"type"`
+	Name string               `json:"name"`
+	Form []NotificationFormVo `json:"form"`
 }
 
 type NotificationFormVo struct {
@@ -29,10 +108,14 @@ type NotificationFormVo struct {
 	FormControlName string `json:"formControlName"`
 	Placeholder     string `json:"placeholder"`
 	Required        bool   `json:"required"`
+	Format          string `json:"format"`
 }
 
 type Notify interface {
 	GetId() string
+	GetType() string
+	GetRateLimit() int
+	GetSchedule() *NotificationSchedule
 	SendNotification(Service, string) error
 	IsEnabled() bool
 	GetForms() []NotificationForm
@@ -42,6 +125,47 @@ type Notify interface {
 	GetServiceDownNotificationTemplate() string
 }
 
+// TemplateRenderer can be implemented by a Notify whose templates are not html, e.g. json payloads.
+// Notifiers without it are rendered with html/template.
+type TemplateRenderer interface {
+	RenderTemplate(name, text string, data TemplateData) (string, error)
+}
+
+// NotificationEvent is the structured form of a notification, including the rendered up or down template as Message.
+// Digests carry the rendered digest template as Message and no service. IsDegraded is set for notifications about the
+// latency, also for the up notification once it is back to normal. Details are the ones the message was rendered with.
+type NotificationEvent struct {
+	// NotificationId is the id of the queued notification, it is the same for all delivery attempts
+	NotificationId   string
+	Service          Service
+	IsUpNotification bool
+	IsDegraded       bool
+	IsDigest         bool
+	Failure          Failure
+	Details          NotificationDetails
+	Message          string
+	Link             string
+	Date             time.Time
+}
+
+// EventNotifier can be implemented by a Notify that needs the up/down state and failure details, e.g. to resolve
+// incidents. SendEvent is called instead of SendNotification.
+type EventNotifier interface {
+	SendEvent(event NotificationEvent) error
+}
+
+// OutputNotifier can be implemented by a Notify whose deliveries produce output, e.g. a command. It is called instead
+// of SendEvent and the output is stored in the delivery log.
+type OutputNotifier interface {
+	SendEventWithOutput(event NotificationEvent) (string, error)
+}
+
+// DigestNotifier can be implemented by a Notify that supports rate limiting. Notifications exceeding the rate limit
+// are coalesced into a digest rendered with the digest template. Notifiers without it are never rate limited.
+type DigestNotifier interface {
+	GetDigestTemplate() string
+}
+
 func MapNotifierToVo(n Notify) NotifierVo {
 	forms := make([]NotificationFormVo, 0, len(n.GetForms()))
 	for _, form := range n.GetForms() {
@@ -49,10 +173,13 @@ func MapNotifierToVo(n Notify) NotifierVo {
 	}
 
 	return NotifierVo{
-		Id:   n.GetId(),
-		Name: n.GetName(),
-		Data: n.GetData(),
-		Form: forms,
+		Id:        n.GetId(),
+		Type:      n.GetType(),
+		Name:      n.GetName(),
+		RateLimit: n.GetRateLimit(),
+		Schedule:  MapNotificationScheduleEntityToVo(n.GetSchedule()),
+		Data:      n.GetData(),
+		Form:      forms,
 	}
 }
 
@@ -63,6 +190,7 @@ func mapNotificationFormToVo(n NotificationForm) NotificationFormVo {
 		FormControlName: n.FormControlName,
 		Placeholder:     n.Placeholder,
 		Required:        n.Required,
+		Format:          n.Format,
 	}
 }
 
@@ -75,3 +203,41 @@ func MapNotifiersToVos(notifies []Notify) []NotifierVo {
 
 	return result
 }
+
+func MapNotifierTypesToVos(notifies []Notify) []NotifierTypeVo {
+	result := make([]NotifierTypeVo, 0, len(notifies))
+
+	for _, notify := range notifies {
+		forms := make([]NotificationFormVo, 0, len(notify.GetForms()))
+		for _, form := range notify.GetForms() {
+			forms = append(forms, mapNotificationFormToVo(form))
+		}
+
+		result = append(result, NotifierTypeVo{
+			Type: notify.GetType(),
+			Name: notify.GetName(),
+			Form: forms,
+		})
+	}
+
+	return result
+}
+
+func MapNotifierInstanceVoToEntity(vo NotifierInstanceVo) NotifierInstance {
+	data := vo.Data
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	now := time.Now()
+	return NotifierInstance{
+		Id:        uuid.New().String(),
+		Type:      vo.Type,
+		Name:      vo.Name,
+		RateLimit: vo.RateLimit,
+		Schedule:  MapNotificationScheduleVoToEntity(vo.Schedule),
+		Data:      data,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
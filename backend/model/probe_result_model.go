@@ -0,0 +1,69 @@
+package model
+
+type AssertionResult struct {
+	Name     string
+	Expected string
+	Actual   string
+	Passed   bool
+}
+
+type AssertionResultVo struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+}
+
+// ProbeResult is the detailed outcome of a single probe. Scheduled checks only keep the outcome and the latency,
+// the dry run returns everything.
+type ProbeResult struct {
+	IsFailure   bool
+	Reason      string
+	LatencyInMs int64
+	StatusCode  int
+	BodyExcerpt string
+	Assertions  []AssertionResult
+}
+
+type ProbeResultVo struct {
+	IsFailure   bool                `json:"isFailure"`
+	Reason      string              `json:"reason"`
+	LatencyInMs int64               `json:"latencyInMs"`
+	StatusCode  int                 `json:"statusCode"`
+	BodyExcerpt string              `json:"bodyExcerpt"`
+	Assertions  []AssertionResultVo `json:"assertions"`
+}
+
+// ToCheckAndFailure converts the result into the entities stored for a scheduled check.
+func (r ProbeResult) ToCheckAndFailure(serviceId string) (*Check, *Failure) {
+	if r.IsFailure {
+		check := NewCheck(serviceId, 0, true)
+		check.StatusCode = r.StatusCode
+		return check, NewFailure(serviceId, r.Reason)
+	}
+
+	check := NewCheck(serviceId, r.LatencyInMs, false)
+	check.StatusCode = r.StatusCode
+	return check, nil
+}
+
+func MapProbeResultToVo(entity ProbeResult) ProbeResultVo {
+	assertions := make([]AssertionResultVo, 0, len(entity.Assertions))
+	for _, assertion := range entity.Assertions {
+		assertions = append(assertions, AssertionResultVo{
+			Name:     assertion.Name,
+			Expected: assertion.Expected,
+			Actual:   assertion.Actual,
+			Passed:   assertion.Passed,
+		})
+	}
+
+	return ProbeResultVo{
+		IsFailure:   entity.IsFailure,
+		Reason:      entity.Reason,
+		LatencyInMs: entity.LatencyInMs,
+		StatusCode:  entity.StatusCode,
+		BodyExcerpt: entity.BodyExcerpt,
+		Assertions:  assertions,
+	}
+}
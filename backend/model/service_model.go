@@ -30,30 +30,97 @@ type Service struct {
 	NotifyAfterNumberOfFailures   int
 	ContinuouslySendNotifications bool
 	Notifiers                     []string
+	Tags                          []string
+	Enabled                       bool
+	ParentIds                     []string
+	EscalationPolicyId            string
+	FlappingThreshold             int
+	FlappingWindow                int
+	IsFlapping                    bool
+	NotificationTemplates         []NotificationTemplate
+	SloTarget                     float64
+	SloWindowInDays               int
+	SloFastBurnRate               float64
+	SloSlowBurnRate               float64
+	SloAlertsOnly                 bool
+	SloBurnState                  SloBurnState
+	LatencyThresholdInMs          int64
+	LatencyPercentile             int
+	LatencyWindow                 int
+	LatencyDeviation              float64
+	IsDegraded                    bool
 	CreatedAt                     time.Time
 	UpdatedAt                     time.Time
 }
 
+// NotificationTemplate overrides the up and down template of a notifier for a single service. Empty templates fall
+// back to the templates of the notifier.
+type NotificationTemplate struct {
+	NotifierId   string `json:"notifierId"`
+	UpTemplate   string `json:"upTemplate"`
+	DownTemplate string `json:"downTemplate"`
+}
+
+type NotificationTemplateVo struct {
+	NotifierId   string `json:"notifierId" binding:"required"`
+	UpTemplate   string `json:"upTemplate"`
+	DownTemplate string `json:"downTemplate"`
+}
+
 type ServiceVo struct {
-	Id                            string      `json:"id"`
-	Name                          string      `json:"name" binding:"required"`
-	Type                          ServiceType `json:"type" binding:"required,oneof=HTTP ICMP_PING"`
-	IntervalInSeconds             int         `json:"intervalInSeconds" binding:"required,min=30,max=1800"`
-	Endpoint                      string      `json:"endpoint" binding:"required"`
-	HttpMethod                    string      `json:"httpMethod"`
-	RequestTimeoutInSeconds       int         `json:"requestTimeoutInSeconds" binding:"min=1,max=180"`
-	HttpHeaders                   string      `json:"httpHeaders"`
-	HttpBody                      string      `json:"httpBody"`
-	ExpectedHttpResponseBody      string      `json:"expectedHttpResponseBody"`
-	ExpectedHttpStatusCode        int         `json:"expectedHttpStatusCode"`
-	FollowRedirects               bool        `json:"followRedirects"`
-	VerifySsl                     bool        `json:"verifySsl"`
-	EnableNotifications           bool        `json:"enableNotifications"`
-	NotifyAfterNumberOfFailures   int         `json:"notifyAfterNumberOfFailures"`
-	ContinuouslySendNotifications bool        `json:"continuouslySendNotifications"`
-	Notifiers                     []string    `json:"notifiers"`
-	CreatedAt                     time.Time   `json:"createdAt"`
-	UpdatedAt                     time.Time   `json:"updatedAt"`
+	Id                            string                   `json:"id"`
+	Name                          string                   `json:"name" binding:"required"`
+	Type                          ServiceType              `json:"type" binding:"required,oneof=HTTP ICMP_PING"`
+	IntervalInSeconds             int                      `json:"intervalInSeconds" binding:"required,min=30,max=1800"`
+	Endpoint                      string                   `json:"endpoint" binding:"required"`
+	HttpMethod                    string                   `json:"httpMethod"`
+	RequestTimeoutInSeconds       int                      `json:"requestTimeoutInSeconds" binding:"min=1,max=180"`
+	HttpHeaders                   string                   `json:"httpHeaders"`
+	HttpBody                      string                   `json:"httpBody"`
+	ExpectedHttpResponseBody      string                   `json:"expectedHttpResponseBody"`
+	ExpectedHttpStatusCode        int                      `json:"expectedHttpStatusCode"`
+	FollowRedirects               bool                     `json:"followRedirects"`
+	VerifySsl                     bool                     `json:"verifySsl"`
+	EnableNotifications           bool                     `json:"enableNotifications"`
+	NotifyAfterNumberOfFailures   int                      `json:"notifyAfterNumberOfFailures"`
+	ContinuouslySendNotifications bool                     `json:"continuouslySendNotifications"`
+	Notifiers                     []string                 `json:"notifiers"`
+	Tags                          []string                 `json:"tags"`
+	Enabled                       bool                     `json:"enabled"`
+	ParentIds                     []string                 `json:"parentIds"`
+	EscalationPolicyId            string                   `json:"escalationPolicyId"`
+	FlappingThreshold             int                      `json:"flappingThreshold" binding:"min=0"`
+	FlappingWindow                int                      `json:"flappingWindow" binding:"min=0,max=100"`
+	IsFlapping                    bool                     `json:"isFlapping"`
+	NotificationTemplates         []NotificationTemplateVo `json:"notificationTemplates" binding:"dive"`
+	SloTarget                     float64                  `json:"sloTarget" binding:"min=0,lt=100"`
+	SloWindowInDays               int                      `json:"sloWindowInDays" binding:"min=0,max=365"`
+	SloFastBurnRate               float64                  `json:"sloFastBurnRate" binding:"min=0"`
+	SloSlowBurnRate               float64                  `json:"sloSlowBurnRate" binding:"min=0"`
+	SloAlertsOnly                 bool                     `json:"sloAlertsOnly"`
+	SloBurnState                  SloBurnState             `json:"sloBurnState"`
+	LatencyThresholdInMs          int64                    `json:"latencyThresholdInMs" binding:"min=0"`
+	LatencyPercentile             int                      `json:"latencyPercentile" binding:"min=0,max=100"`
+	LatencyWindow                 int                      `json:"latencyWindow" binding:"min=0,max=100"`
+	LatencyDeviation              float64                  `json:"latencyDeviation" binding:"min=0"`
+	IsDegraded                    bool                     `json:"isDegraded"`
+	CreatedAt                     time.Time                `json:"createdAt"`
+	UpdatedAt                     time.Time                `json:"updatedAt"`
+}
+
+// NotificationTemplate returns the up or down template the service uses for the notifier or an empty string if the
+// template of the notifier is used.
+func (s Service) NotificationTemplate(notifierId string, isUpNotification bool) string {
+	for _, notificationTemplate := range s.NotificationTemplates {
+		if notificationTemplate.NotifierId != notifierId {
+			continue
+		}
+		if isUpNotification {
+			return notificationTemplate.UpTemplate
+		}
+		return notificationTemplate.DownTemplate
+	}
+	return ""
 }
 
 func MapServiceVoToEntity(vo ServiceVo) Service {
@@ -75,6 +142,22 @@ func MapServiceVoToEntity(vo ServiceVo) Service {
 		NotifyAfterNumberOfFailures:   vo.NotifyAfterNumberOfFailures,
 		ContinuouslySendNotifications: vo.ContinuouslySendNotifications,
 		Notifiers:                     vo.Notifiers,
+		Tags:                          mapNilSliceToEmpty(vo.Tags),
+		Enabled:                       true,
+		ParentIds:                     mapNilSliceToEmpty(vo.ParentIds),
+		EscalationPolicyId:            vo.EscalationPolicyId,
+		FlappingThreshold:             vo.FlappingThreshold,
+		FlappingWindow:                vo.FlappingWindow,
+		NotificationTemplates:         mapNotificationTemplateVosToEntities(vo.NotificationTemplates),
+		SloTarget:                     vo.SloTarget,
+		SloWindowInDays:               vo.SloWindowInDays,
+		SloFastBurnRate:               vo.SloFastBurnRate,
+		SloSlowBurnRate:               vo.SloSlowBurnRate,
+		SloAlertsOnly:                 vo.SloAlertsOnly,
+		LatencyThresholdInMs:          vo.LatencyThresholdInMs,
+		LatencyPercentile:             vo.LatencyPercentile,
+		LatencyWindow:                 vo.LatencyWindow,
+		LatencyDeviation:              vo.LatencyDeviation,
 		CreatedAt:                     time.Now(),
 		UpdatedAt:                     time.Now(),
 	}
@@ -99,6 +182,25 @@ func MapServiceEntityToVo(entity Service) ServiceVo {
 		NotifyAfterNumberOfFailures:   entity.NotifyAfterNumberOfFailures,
 		ContinuouslySendNotifications: entity.ContinuouslySendNotifications,
 		Notifiers:                     entity.Notifiers,
+		Tags:                          entity.Tags,
+		Enabled:                       entity.Enabled,
+		ParentIds:                     entity.ParentIds,
+		EscalationPolicyId:            entity.EscalationPolicyId,
+		FlappingThreshold:             entity.FlappingThreshold,
+		FlappingWindow:                entity.FlappingWindow,
+		IsFlapping:                    entity.IsFlapping,
+		NotificationTemplates:         mapNotificationTemplateEntitiesToVos(entity.NotificationTemplates),
+		SloTarget:                     entity.SloTarget,
+		SloWindowInDays:               entity.SloWindowInDays,
+		SloFastBurnRate:               entity.SloFastBurnRate,
+		SloSlowBurnRate:               entity.SloSlowBurnRate,
+		SloAlertsOnly:                 entity.SloAlertsOnly,
+		SloBurnState:                  entity.SloBurnState,
+		LatencyThresholdInMs:          entity.LatencyThresholdInMs,
+		LatencyPercentile:             entity.LatencyPercentile,
+		LatencyWindow:                 entity.LatencyWindow,
+		LatencyDeviation:              entity.LatencyDeviation,
+		IsDegraded:                    entity.IsDegraded,
 		CreatedAt:                     entity.CreatedAt,
 		UpdatedAt:                     entity.UpdatedAt,
 	}
@@ -111,3 +213,40 @@ func MapServiceEntitiesToVos(entities []Service) []ServiceVo {
 	}
 	return result
 }
+
+func mapNotificationTemplateVosToEntities(vos []NotificationTemplateVo) []NotificationTemplate {
+	result := make([]NotificationTemplate, 0, len(vos))
+	for _, vo := range vos {
+		result = append(result, NotificationTemplate{
+			NotifierId:   vo.NotifierId,
+			UpTemplate:   vo.UpTemplate,
+			DownTemplate: vo.DownTemplate,
+		})
+	}
+	return result
+}
+
+func mapNotificationTemplateEntitiesToVos(entities []NotificationTemplate) []NotificationTemplateVo {
+	result := make([]NotificationTemplateVo, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, NotificationTemplateVo{
+			NotifierId:   entity.NotifierId,
+			UpTemplate:   entity.UpTemplate,
+			DownTemplate: entity.DownTemplate,
+		})
+	}
+	return result
+}
+
+// ServiceIdsVo selects the services of a bulk operation. If All is set the ids are ignored.
+type ServiceIdsVo struct {
+	Ids []string `json:"ids"`
+	All bool     `json:"all"`
+}
+
+func mapNilSliceToEmpty(values []string) []string {
+	if values == nil {
+		return make([]string, 0)
+	}
+	return values
+}
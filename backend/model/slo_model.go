@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+const (
+	SloBurnStateNone SloBurnState = ""
+	// SloBurnStateSlow is set while the error budget burns faster than the slow burn rate over the last 6 hours
+	SloBurnStateSlow SloBurnState = "SLOW"
+	// SloBurnStateFast is set while the error budget burns faster than the fast burn rate over the last hour
+	SloBurnStateFast SloBurnState = "FAST"
+
+	DefaultSloWindowInDays = 30
+	DefaultSloFastBurnRate = 14.4
+	DefaultSloSlowBurnRate = 6
+
+	SloFastBurnWindow = time.Hour
+	SloSlowBurnWindow = 6 * time.Hour
+)
+
+type SloBurnState string
+
+// SloStatus is the error budget of a service over its SLO window and the current burn rates. A burn rate of 1
+// consumes exactly the whole budget within the window.
+type SloStatus struct {
+	Target                float64
+	WindowInDays          int
+	Uptime                float64
+	ErrorBudgetRemaining  float64
+	FastBurnRate          float64
+	SlowBurnRate          float64
+	FastBurnRateThreshold float64
+	SlowBurnRateThreshold float64
+	BurnState             SloBurnState
+}
+
+type SloStatusVo struct {
+	Target                float64      `json:"target"`
+	WindowInDays          int          `json:"windowInDays"`
+	Uptime                float64      `json:"uptime"`
+	ErrorBudgetRemaining  float64      `json:"errorBudgetRemaining"`
+	FastBurnRate          float64      `json:"fastBurnRate"`
+	SlowBurnRate          float64      `json:"slowBurnRate"`
+	FastBurnRateThreshold float64      `json:"fastBurnRateThreshold"`
+	SlowBurnRateThreshold float64      `json:"slowBurnRateThreshold"`
+	BurnState             SloBurnState `json:"burnState"`
+}
+
+// SloWindow returns the SLO window of the service, 30 days if not set.
+func (s Service) SloWindow() time.Duration {
+	if s.SloWindowInDays == 0 {
+		return DefaultSloWindowInDays * 24 * time.Hour
+	}
+	return time.Duration(s.SloWindowInDays) * 24 * time.Hour
+}
+
+// SloBurnRateThresholds returns the fast and slow burn rate thresholds of the service or their defaults.
+func (s Service) SloBurnRateThresholds() (float64, float64) {
+	fast, slow := s.SloFastBurnRate, s.SloSlowBurnRate
+	if fast == 0 {
+		fast = DefaultSloFastBurnRate
+	}
+	if slow == 0 {
+		slow = DefaultSloSlowBurnRate
+	}
+	return fast, slow
+}
+
+// BurnRate returns how many times faster than allowed by the SLO target the error budget is consumed, given the
+// number of successful and failed checks.
+func BurnRate(target, success, failures float64) float64 {
+	if success+failures == 0 || target >= 100 {
+		return 0
+	}
+	errorRatio := failures / (success + failures)
+	return errorRatio / (1 - target/100)
+}
+
+func MapSloStatusEntityToVo(entity SloStatus) SloStatusVo {
+	return SloStatusVo{
+		Target:                entity.Target,
+		WindowInDays:          entity.WindowInDays,
+		Uptime:                entity.Uptime,
+		ErrorBudgetRemaining:  entity.ErrorBudgetRemaining,
+		FastBurnRate:          entity.FastBurnRate,
+		SlowBurnRate:          entity.SlowBurnRate,
+		FastBurnRateThreshold: entity.FastBurnRateThreshold,
+		SlowBurnRateThreshold: entity.SlowBurnRateThreshold,
+		BurnState:             entity.BurnState,
+	}
+}
@@ -1,7 +1,54 @@
 package model
 
+import "time"
+
 type TemplateData struct {
-	Name   string
-	Date   string
-	Reason string
+	// IsUp is true for up notifications
+	IsUp bool
+	// IsDegraded is true for notifications about the latency of a service. A down notification is sent when the
+	// service is up but slower than its latency threshold, an up notification when the latency is back to normal
+	IsDegraded bool
+	Name       string
+	Date       string
+	Reason     string
+	Link       string
+	Endpoint   string
+	Type       ServiceType
+
+	// StatusCode is the http status code of the check, zero for other service types
+	StatusCode  int
+	LatencyInMs int64
+	// ConsecutiveFailures is the number of failures of the outage
+	ConsecutiveFailures int
+	// DownSince is the time the outage started, empty if the service was not down
+	DownSince string
+	// Downtime is the duration of the outage, only set for up notifications
+	Downtime time.Duration
+
+	// DownCount, UpCount, Down and Up are only set when a digest is rendered
+	DownCount int
+	UpCount   int
+	Down      []DigestEntry
+	Up        []DigestEntry
+}
+
+// DigestEntry is the latest state of one service in a digest.
+type DigestEntry struct {
+	Name string
+	// IsDegraded is true if the latency of the service is degraded or, in Up, back to normal
+	IsDegraded bool
+	Date       string
+	Reason     string
+	Link       string
+}
+
+// NotificationDetails describe the check and the outage a notification is sent for. They are stored with the
+// notification, so that it can be rendered again for another notifier.
+type NotificationDetails struct {
+	Reason              string     `json:"reason"`
+	IsDegraded          bool       `json:"isDegraded"`
+	StatusCode          int        `json:"statusCode"`
+	LatencyInMs         int64      `json:"latencyInMs"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	DownSince           *time.Time `json:"downSince"`
 }
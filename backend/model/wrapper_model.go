@@ -21,3 +21,33 @@ type FailureWrapperVo struct {
 type NotifierWrapperVo struct {
 	Data []NotifierVo `json:"data"`
 }
+
+type NotifierTypeWrapperVo struct {
+	Data []NotifierTypeVo `json:"data"`
+}
+
+type MaintenanceWrapperVo struct {
+	Data []MaintenanceVo `json:"data"`
+}
+
+type ServiceDependencyWrapperVo struct {
+	Data []ServiceDependencyVo `json:"data"`
+}
+
+type NotificationWrapperVo struct {
+	Data       []NotificationVo `json:"data"`
+	TotalCount int              `json:"totalCount"`
+	PageSize   int              `json:"pageSize"`
+	Page       int              `json:"page"`
+}
+
+type EscalationPolicyWrapperVo struct {
+	Data []EscalationPolicyVo `json:"data"`
+}
+
+type IncidentWrapperVo struct {
+	Data       []IncidentVo `json:"data"`
+	TotalCount int          `json:"totalCount"`
+	PageSize   int          `json:"pageSize"`
+	Page       int          `json:"page"`
+}
@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestChatNotifiers(url string) []model.Notify {
+	store := viper.New()
+	for _, id := range []string{"SLACK", "TEAMS", "DISCORD", "MATTERMOST"} {
+		store.Set("NOTIFIER_"+id+"_ENABLED", true)
+		store.Set("NOTIFIER_"+id+"_WEBHOOKURL", url)
+	}
+
+	return []model.Notify{
+		NewSlackNotifier(store),
+		NewTeamsNotifier(store),
+		NewDiscordNotifier(store),
+		NewMattermostNotifier(store),
+	}
+}
+
+func TestChatNotifiersShouldRenderValidJsonFromDefaultTemplates(t *testing.T) {
+	data := []model.TemplateData{
+		{Name: `My "quoted" Service`, Reason: "connection\nrefused", Date: "2020-12-20T10:00:00Z"},
+		{Name: "Service", Reason: "timeout", Date: "2020-12-20T10:00:00Z", Link: "https://monhttp.example.com/services/1"},
+	}
+
+	for _, notify := range newTestChatNotifiers("") {
+		for _, templateData := range data {
+			for _, templateText := range []string{notify.GetServiceUpNotificationTemplate(), notify.GetServiceDownNotificationTemplate()} {
+				message, err := RenderTemplate(notify, templateText, templateData)
+				assert.Nil(t, err, notify.GetId())
+
+				var payload map[string]interface{}
+				assert.Nil(t, json.Unmarshal([]byte(message), &payload), "%s: %s", notify.GetId(), message)
+				assert.Contains(t, message, jsonEscaped(templateData.Name), notify.GetId())
+
+				if len(templateData.Link) > 0 {
+					assert.Contains(t, message, templateData.Link, notify.GetId())
+				}
+			}
+		}
+	}
+}
+
+func TestChatNotifiersShouldUseStatusColors(t *testing.T) {
+	data := model.TemplateData{Name: "Service", Reason: "timeout", Date: "2020-12-20T10:00:00Z"}
+	upColors := map[string]string{"slack": "#2eb886", "teams": `"Good"`, "discord": "3066993", "mattermost": "#2eb886"}
+	downColors := map[string]string{"slack": "#e01e5a", "teams": `"Attention"`, "discord": "15158332", "mattermost": "#e01e5a"}
+
+	for _, notify := range newTestChatNotifiers("") {
+		upMessage, err := RenderTemplate(notify, notify.GetServiceUpNotificationTemplate(), data)
+		assert.Nil(t, err)
+		assert.Contains(t, upMessage, upColors[notify.GetId()], notify.GetId())
+
+		downMessage, err := RenderTemplate(notify, notify.GetServiceDownNotificationTemplate(), data)
+		assert.Nil(t, err)
+		assert.Contains(t, downMessage, downColors[notify.GetId()], notify.GetId())
+		assert.Contains(t, downMessage, "Reason: timeout", notify.GetId())
+	}
+}
+
+func TestChatNotifiersShouldPostPayloadToWebhookUrl(t *testing.T) {
+	var receivedContentTypes []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedContentTypes = append(receivedContentTypes, request.Header.Get("Content-Type"))
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	for _, notify := range newTestChatNotifiers(server.URL) {
+		assert.Nil(t, notify.SendNotification(model.Service{}, `{}`), notify.GetId())
+	}
+
+	assert.Equal(t, []string{"application/json", "application/json", "application/json", "application/json"}, receivedContentTypes)
+}
+
+func TestChatNotifiersShouldReturnErrorOnFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+		_, _ = writer.Write([]byte("invalid_payload"))
+	}))
+	defer server.Close()
+
+	for _, notify := range newTestChatNotifiers(server.URL) {
+		err := notify.SendNotification(model.Service{}, `{}`)
+		assert.NotNil(t, err, notify.GetId())
+		assert.Contains(t, err.Error(), "invalid_payload", notify.GetId())
+	}
+}
+
+func jsonEscaped(value string) string {
+	content, _ := json.Marshal(value)
+	return strings.Trim(string(content), `"`)
+}
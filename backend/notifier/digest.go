@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDigestTemplate = `<b>{{.DownCount}} services down, {{.UpCount}} recovered</b>` +
+		`{{range .Down}}<br>Service '{{.Name}}' is {{if .IsDegraded}}degraded{{else}}down{{end}}. Reason: '{{.Reason}}'{{end}}` +
+		`{{range .Up}}<br>{{if .IsDegraded}}Latency of service '{{.Name}}' is back to normal{{else}}Service '{{.Name}}' is up again!{{end}}{{end}}`
+	defaultTextDigestTemplate = "{{.DownCount}} services down, {{.UpCount}} recovered" +
+		"{{range .Down}}\nService '{{.Name}}' is {{if .IsDegraded}}degraded{{else}}down{{end}}. Reason: '{{.Reason}}'{{end}}" +
+		"{{range .Up}}\n{{if .IsDegraded}}Latency of service '{{.Name}}' is back to normal{{else}}Service '{{.Name}}' is up again!{{end}}{{end}}"
+
+	// maxDigestEntries limits the services listed per state. The counts always contain all services.
+	maxDigestEntries = 20
+)
+
+// digestTemplate returns the digest template NOTIFIER_<TYPE>_DIGEST_TEMPLATE of the store or the default template of
+// the type if it is not set.
+func digestTemplate(store *viper.Viper, notifierType, defaultTemplate string) string {
+	if value := store.GetString(fmt.Sprintf("NOTIFIER_%s_DIGEST_TEMPLATE", strings.ToUpper(notifierType))); len(value) > 0 {
+		return value
+	}
+	return defaultTemplate
+}
+
+// DigestItem is the latest notification of a service that was coalesced into a digest.
+type DigestItem struct {
+	ServiceId        string
+	ServiceName      string
+	IsUpNotification bool
+	IsDegraded       bool
+	Reason           string
+	Date             time.Time
+}
+
+// NewDigestData returns the template data of a digest. Only the latest item of every service is counted.
+func NewDigestData(items []DigestItem, date time.Time) model.TemplateData {
+	latest := make(map[string]DigestItem)
+	order := make([]string, 0)
+	for _, item := range items {
+		current, exists := latest[item.ServiceId]
+		if !exists {
+			order = append(order, item.ServiceId)
+		}
+		if !exists || !item.Date.Before(current.Date) {
+			latest[item.ServiceId] = item
+		}
+	}
+
+	data := model.TemplateData{
+		Name: "monhttp",
+		Date: date.Format(time.RFC3339),
+		Down: make([]model.DigestEntry, 0),
+		Up:   make([]model.DigestEntry, 0),
+	}
+
+	for _, serviceId := range order {
+		item := latest[serviceId]
+		entry := model.DigestEntry{
+			Name:       item.ServiceName,
+			IsDegraded: item.IsDegraded,
+			Date:       item.Date.Format(time.RFC3339),
+			Reason:     item.Reason,
+			Link:       ServiceLink(item.ServiceId),
+		}
+
+		if item.IsUpNotification {
+			data.UpCount++
+			if len(data.Up) < maxDigestEntries {
+				data.Up = append(data.Up, entry)
+			}
+		} else {
+			data.DownCount++
+			if len(data.Down) < maxDigestEntries {
+				data.Down = append(data.Down, entry)
+			}
+		}
+	}
+	return data
+}
+
+// RenderDigest renders the digest template of the notifier.
+func RenderDigest(notifier model.Notify, data model.TemplateData) (string, error) {
+	digestNotifier, ok := notifier.(model.DigestNotifier)
+	if !ok {
+		return "", fmt.Errorf("notifier '%s' does not support digests", notifier.GetId())
+	}
+	return RenderTemplate(notifier, digestNotifier.GetDigestTemplate(), data)
+}
+
+// eventTitle returns the title of push notifications.
+func eventTitle(event model.NotificationEvent) string {
+	if event.IsDigest {
+		return "monhttp digest"
+	}
+	if event.IsUpNotification && event.IsDegraded {
+		return fmt.Sprintf("%s is back to normal", event.Service.Name)
+	}
+	if event.IsUpNotification {
+		return fmt.Sprintf("%s is up", event.Service.Name)
+	}
+	if event.IsDegraded {
+		return fmt.Sprintf("%s is degraded", event.Service.Name)
+	}
+	return fmt.Sprintf("%s is down", event.Service.Name)
+}
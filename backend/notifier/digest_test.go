@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewDigestDataShouldCountLatestNotificationOfEveryService(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+	items := []DigestItem{
+		{ServiceId: "1", ServiceName: "Api", Reason: "timeout", Date: date},
+		{ServiceId: "2", ServiceName: "Web", Reason: "connection refused", Date: date},
+		{ServiceId: "1", ServiceName: "Api", IsUpNotification: true, Date: date.Add(time.Minute)},
+		{ServiceId: "3", ServiceName: "Db", Reason: "timeout", Date: date.Add(time.Minute)},
+		{ServiceId: "4", ServiceName: "Queue", IsDegraded: true, Reason: "latency", Date: date.Add(time.Minute)},
+	}
+
+	data := NewDigestData(items, date.Add(5*time.Minute))
+
+	assert.Equal(t, 3, data.DownCount)
+	assert.Equal(t, 1, data.UpCount)
+	assert.Equal(t, "Web", data.Down[0].Name)
+	assert.Equal(t, "connection refused", data.Down[0].Reason)
+	assert.Equal(t, "Db", data.Down[1].Name)
+	assert.Equal(t, "Queue", data.Down[2].Name)
+	assert.True(t, data.Down[2].IsDegraded)
+	assert.Equal(t, "Api", data.Up[0].Name)
+	assert.Equal(t, "2020-12-20T10:05:00Z", data.Date)
+}
+
+func TestNewDigestDataShouldLimitListedServices(t *testing.T) {
+	items := make([]DigestItem, 0)
+	for i := 0; i < 30; i++ {
+		items = append(items, DigestItem{ServiceId: fmt.Sprint(i), ServiceName: fmt.Sprintf("Service %d", i), Date: time.Now()})
+	}
+
+	data := NewDigestData(items, time.Now())
+
+	assert.Equal(t, 30, data.DownCount)
+	assert.Equal(t, maxDigestEntries, len(data.Down))
+	assert.Equal(t, 0, len(data.Up))
+}
+
+func TestRenderDigestShouldUseDigestTemplate(t *testing.T) {
+	store := viper.New()
+	store.Set("NOTIFIER_TELEGRAM_DIGEST_TEMPLATE", "{{.DownCount}} down, {{.UpCount}} up")
+
+	data := NewDigestData([]DigestItem{{ServiceId: "1", ServiceName: "Api", Date: time.Now()}}, time.Now())
+
+	message, err := RenderDigest(NewTelegramNotifier(store), data)
+	assert.Nil(t, err)
+	assert.Equal(t, "1 down, 0 up", message)
+}
+
+func TestRenderDigestShouldReturnErrorForIncidentNotifiers(t *testing.T) {
+	_, err := RenderDigest(NewPagerDutyNotifier(viper.New()), model.TemplateData{})
+	assert.NotNil(t, err)
+}
+
+func TestJsonNotifiersShouldRenderValidJsonFromDefaultDigestTemplates(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+	data := []model.TemplateData{
+		NewDigestData(nil, date),
+		NewDigestData([]DigestItem{{ServiceId: "1", ServiceName: `My "quoted" Service`, Reason: "connection\nrefused", Date: date}}, date),
+		NewDigestData([]DigestItem{
+			{ServiceId: "1", ServiceName: "Api", Reason: "timeout", Date: date},
+			{ServiceId: "2", ServiceName: "Web", Reason: "timeout", Date: date},
+			{ServiceId: "3", ServiceName: "Db", IsUpNotification: true, Date: date},
+			{ServiceId: "4", ServiceName: "Queue", IsUpNotification: true, Date: date},
+		}, date),
+		NewDigestData([]DigestItem{
+			{ServiceId: "1", ServiceName: "Api", IsDegraded: true, Reason: "latency", Date: date},
+			{ServiceId: "2", ServiceName: "Web", IsUpNotification: true, IsDegraded: true, Date: date},
+		}, date),
+	}
+
+	notifiers := append(newTestChatNotifiers(""), NewWebhookNotifier(viper.New()))
+	for _, notify := range notifiers {
+		for _, templateData := range data {
+			message, err := RenderDigest(notify, templateData)
+			assert.Nil(t, err, notify.GetId())
+
+			var payload map[string]interface{}
+			assert.Nil(t, json.Unmarshal([]byte(message), &payload), "%s: %s", notify.GetId(), message)
+			assert.Contains(t, message, fmt.Sprintf("%d", templateData.DownCount), notify.GetId())
+
+			for _, entry := range append(templateData.Down, templateData.Up...) {
+				assert.Contains(t, message, jsonEscaped(entry.Name), notify.GetId())
+			}
+		}
+	}
+}
+
+func TestRenderDigestShouldRenderLatencyEntries(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+	data := NewDigestData([]DigestItem{
+		{ServiceId: "1", ServiceName: "Api", IsDegraded: true, Reason: "latency", Date: date},
+		{ServiceId: "2", ServiceName: "Web", IsUpNotification: true, IsDegraded: true, Date: date},
+	}, date)
+
+	message, err := RenderDigest(NewExecNotifier(viper.New()), data)
+	assert.Nil(t, err)
+	assert.Equal(t, "1 services down, 1 recovered\nService 'Api' is degraded. Reason: 'latency'\n"+
+		"Latency of service 'Web' is back to normal", message)
+}
+
+func TestNewNotifierShouldUseRateLimitOfInstance(t *testing.T) {
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "telegram", Name: "Team A", RateLimit: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, 20, notify.GetRateLimit())
+}
+
+func TestEventTitleShouldNameDigests(t *testing.T) {
+	service := model.Service{Name: "Api"}
+
+	assert.Equal(t, "monhttp digest", eventTitle(model.NotificationEvent{Service: service, IsDigest: true}))
+	assert.Equal(t, "Api is up", eventTitle(model.NotificationEvent{Service: service, IsUpNotification: true}))
+	assert.Equal(t, "Api is back to normal", eventTitle(model.NotificationEvent{Service: service, IsUpNotification: true, IsDegraded: true}))
+	assert.Equal(t, "Api is down", eventTitle(model.NotificationEvent{Service: service}))
+}
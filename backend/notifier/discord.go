@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+const (
+	defaultDiscordUpTemplate     = `{"embeds": [{"title": {{if .IsDegraded}}{{json (printf "Latency of %s is back to normal" .Name)}}{{else}}{{json (printf "%s is up again" .Name)}}{{end}}, "color": 3066993, {{if .Link}}"url": {{json .Link}}, {{end}}"timestamp": {{json .Date}}}]}`
+	defaultDiscordDownTemplate   = `{"embeds": [{"title": {{if .IsDegraded}}{{json (printf "%s is degraded" .Name)}}{{else}}{{json (printf "%s is down" .Name)}}{{end}}, "description": {{json (printf "Reason: %s" .Reason)}}, "color": 15158332, {{if .Link}}"url": {{json .Link}}, {{end}}"timestamp": {{json .Date}}}]}`
+	defaultDiscordDigestTemplate = `{"embeds": [{"title": {{json (printf "%d services down, %d recovered" .DownCount .UpCount)}}, "color": 15844367, "fields": [{{range $i, $e := .Down}}{{if $i}}, {{end}}{"name": {{if $e.IsDegraded}}{{json (printf "%s is degraded" $e.Name)}}{{else}}{{json (printf "%s is down" $e.Name)}}{{end}}, "value": {{json (printf "Reason: %s" $e.Reason)}}}{{end}}{{if and .Down .Up}}, {{end}}{{range $i, $e := .Up}}{{if $i}}, {{end}}{"name": {{if $e.IsDegraded}}{{json (printf "Latency of %s is back to normal" $e.Name)}}{{else}}{{json (printf "%s is up again" $e.Name)}}{{end}}, "value": {{json $e.Date}}}{{end}}], "timestamp": {{json .Date}}}]}`
+)
+
+type DiscordNotifier struct {
+	model.Notifier
+	WebhookUrl string
+	client     *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "discord",
+		New:  func(store *viper.Viper) model.Notify { return NewDiscordNotifier(store) },
+	})
+}
+
+func NewDiscordNotifier(store *viper.Viper) *DiscordNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_DISCORD_ENABLED")
+	data["webhookUrl"] = store.GetString("NOTIFIER_DISCORD_WEBHOOKURL")
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_DISCORD_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultDiscordUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_DISCORD_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultDiscordDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "discord", defaultDiscordDigestTemplate)
+
+	return &DiscordNotifier{
+		Notifier: model.Notifier{
+			Id:      "discord",
+			Name:    "Discord",
+			Enabled: store.GetBool("NOTIFIER_DISCORD_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Webhook url",
+					FormControlName: "webhookUrl",
+					Placeholder:     "https://discord.com/api/webhooks/000000000000000000/XXXXXXXX",
+					Required:        true,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Embed payload for {{.Name}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Embed payload for {{.Name}} and {{.Reason}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		WebhookUrl: store.GetString("NOTIFIER_DISCORD_WEBHOOKURL"),
+		client:     &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *DiscordNotifier) SendNotification(service model.Service, message string) error {
+	return postJson(n.client, n.WebhookUrl, message)
+}
+
+func (n *DiscordNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *DiscordNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *DiscordNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *DiscordNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *DiscordNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *DiscordNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *DiscordNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDiscordUpTemplate
+}
+
+func (n *DiscordNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDiscordDownTemplate
+}
+
+func (n *DiscordNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDiscordDigestTemplate
+}
@@ -1,21 +1,81 @@
 package notifier
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/koloo91/monhttp/model"
 	"github.com/spf13/viper"
+	"net"
 	"net/smtp"
+	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	EMailSecurityAuto     = "auto"
+	EMailSecurityTls      = "tls"
+	EMailSecurityStartTls = "starttls"
+	EMailSecurityNone     = "none"
+
+	EMailAuthPlain   = "plain"
+	EMailAuthLogin   = "login"
+	EMailAuthCramMd5 = "cram-md5"
+	EMailAuthNone    = "none"
+
+	defaultEMailSubjectTemplate = "{{.Name}} is {{if and .IsUp .IsDegraded}}back to normal{{else if .IsUp}}up{{else if .IsDegraded}}degraded{{else}}down{{end}}"
+	emailTimeout                = 30 * time.Second
+	implicitTlsPort             = 465
 )
 
 type EMailNotifier struct {
 	model.Notifier
-	Host    string
-	Port    int
-	From    string
-	To      []string
-	Subject string
-	Auth    smtp.Auth
+	Host     string
+	Port     int
+	From     string
+	To       []string
+	Subject  string
+	Security string
+	AuthMode string
+	Auth     smtp.Auth
+}
+
+func init() {
+	Register(NotifierType{
+		Type:     "email",
+		New:      func(store *viper.Viper) model.Notify { return NewEMailNotifier(store) },
+		Validate: validateEMailData,
+	})
+}
+
+// validateEMailData rejects unknown security and auth modes, which would otherwise send the credentials unencrypted.
+func validateEMailData(notify model.Notify, _ map[string]interface{}) ([]interface{}, error) {
+	n := notify.(*EMailNotifier)
+	result := make([]interface{}, 0)
+
+	if err := checkEMailSecurity(n.Security); err != nil {
+		result = append(result, model.FieldErrorVo{Field: "security", Message: err.Error()})
+	}
+	if err := checkEMailAuthMode(n.AuthMode); err != nil {
+		result = append(result, model.FieldErrorVo{Field: "authMode", Message: err.Error()})
+	}
+	return result, nil
+}
+
+func checkEMailSecurity(security string) error {
+	switch security {
+	case EMailSecurityAuto, EMailSecurityTls, EMailSecurityStartTls, EMailSecurityNone:
+		return nil
+	}
+	return fmt.Errorf("'%s' is not one of auto, tls, starttls or none", security)
+}
+
+func checkEMailAuthMode(authMode string) error {
+	switch authMode {
+	case EMailAuthPlain, EMailAuthLogin, EMailAuthCramMd5, EMailAuthNone:
+		return nil
+	}
+	return fmt.Errorf("'%s' is not one of plain, login, cram-md5 or none", authMode)
 }
 
 func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
@@ -23,11 +83,26 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 	data["enabled"] = store.GetBool("NOTIFIER_EMAIL_ENABLED")
 	data["host"] = store.GetString("NOTIFIER_EMAIL_HOST")
 	data["port"] = store.GetInt("NOTIFIER_EMAIL_PORT")
+	data["security"] = strings.ToLower(store.GetString("NOTIFIER_EMAIL_SECURITY"))
+	data["authMode"] = strings.ToLower(store.GetString("NOTIFIER_EMAIL_AUTHMODE"))
+	data["username"] = store.GetString("NOTIFIER_EMAIL_USERNAME")
 	data["from"] = store.GetString("NOTIFIER_EMAIL_FROM")
 	data["password"] = store.GetString("NOTIFIER_EMAIL_PASSWORD")
 	data["subject"] = store.GetString("NOTIFIER_EMAIL_SUBJECT")
 	data["to"] = strings.Join(store.GetStringSlice("NOTIFIER_EMAIL_TO"), ",")
 
+	if value := data["security"].(string); len(value) == 0 {
+		data["security"] = EMailSecurityAuto
+	}
+
+	if value := data["authMode"].(string); len(value) == 0 {
+		data["authMode"] = EMailAuthPlain
+	}
+
+	if value := data["subject"].(string); len(value) == 0 {
+		data["subject"] = defaultEMailSubjectTemplate
+	}
+
 	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_EMAIL_SERVICE_UP_TEMPLATE")
 	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
 		data["SERVICE_UP_TEMPLATE"] = defaultUpTemplate
@@ -38,16 +113,45 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 		data["SERVICE_DOWN_TEMPLATE"] = defaultDownTemplate
 	}
 
-	username := store.GetString("NOTIFIER_EMAIL_FROM")
-	password := store.GetString("NOTIFIER_EMAIL_PASSWORD")
-	host := store.GetString("NOTIFIER_EMAIL_HOST")
-	auth := smtp.PlainAuth("", username, password, host)
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "email", defaultDigestTemplate)
+
+	host := data["host"].(string)
+	port := data["port"].(int)
+
+	security := data["security"].(string)
+	if security == EMailSecurityAuto && port == implicitTlsPort {
+		security = EMailSecurityTls
+	}
+
+	// the from address is used as username if none is configured
+	username := data["username"].(string)
+	if len(username) == 0 {
+		username = data["from"].(string)
+	}
+
+	// unknown modes have no auth, send refuses to use them
+	var auth smtp.Auth
+	switch data["authMode"].(string) {
+	case EMailAuthPlain:
+		auth = smtp.PlainAuth("", username, data["password"].(string), host)
+	case EMailAuthLogin:
+		auth = &loginAuth{username: username, password: data["password"].(string), host: host}
+	case EMailAuthCramMd5:
+		auth = smtp.CRAMMD5Auth(username, data["password"].(string))
+	}
+
+	to := make([]string, 0)
+	for _, address := range strings.Split(store.GetString("NOTIFIER_EMAIL_TO"), ",") {
+		if address = strings.TrimSpace(address); len(address) > 0 {
+			to = append(to, address)
+		}
+	}
 
 	return &EMailNotifier{
 		Notifier: model.Notifier{
 			Id:      "email",
 			Name:    "E-Mail",
-			Enabled: viper.GetBool("NOTIFIER_EMAIL_ENABLED"),
+			Enabled: store.GetBool("NOTIFIER_EMAIL_ENABLED"),
 			Data:    data,
 			Form: []model.NotificationForm{
 				{
@@ -70,6 +174,28 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 					FormControlName: "port",
 					Placeholder:     "587",
 					Required:        true,
+					Format:          model.FormFormatPort,
+				},
+				{
+					Type:            "text",
+					Title:           "Security",
+					FormControlName: "security",
+					Placeholder:     "auto, tls, starttls or none",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Authentication",
+					FormControlName: "authMode",
+					Placeholder:     "plain, login, cram-md5 or none",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Username",
+					FormControlName: "username",
+					Placeholder:     "the from address if empty",
+					Required:        false,
 				},
 				{
 					Type:            "text",
@@ -77,13 +203,14 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 					FormControlName: "from",
 					Placeholder:     "gululu@example.com",
 					Required:        true,
+					Format:          model.FormFormatEmail,
 				},
 				{
 					Type:            "password",
 					Title:           "Password",
 					FormControlName: "password",
 					Placeholder:     "your string password",
-					Required:        true,
+					Required:        false,
 				},
 				{
 					Type:            "text",
@@ -91,13 +218,15 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 					FormControlName: "to",
 					Placeholder:     "gululu@example.com,example@example.com",
 					Required:        true,
+					Format:          model.FormFormatEmailList,
 				},
 				{
 					Type:            "text",
-					Title:           "Subject",
+					Title:           "Subject template",
 					FormControlName: "subject",
-					Placeholder:     "Service notification from monhttp",
+					Placeholder:     defaultEMailSubjectTemplate,
 					Required:        true,
+					Format:          model.FormFormatTemplate,
 				},
 				{
 					Type:            "textarea",
@@ -105,6 +234,7 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 					FormControlName: "SERVICE_UP_TEMPLATE",
 					Placeholder:     "Service {{.Name}} is up",
 					Required:        true,
+					Format:          model.FormFormatTemplate,
 				},
 				{
 					Type:            "textarea",
@@ -112,39 +242,129 @@ func NewEMailNotifier(store *viper.Viper) *EMailNotifier {
 					FormControlName: "SERVICE_DOWN_TEMPLATE",
 					Placeholder:     "Service {{.Name}} is down",
 					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
 				},
 			},
 		},
-		Host:    store.GetString("NOTIFIER_EMAIL_HOST"),
-		Port:    store.GetInt("NOTIFIER_EMAIL_PORT"),
-		From:    store.GetString("NOTIFIER_EMAIL_FROM"),
-		To:      strings.Split(store.GetString("NOTIFIER_EMAIL_TO"), ","),
-		Subject: store.GetString("NOTIFIER_EMAIL_SUBJECT"),
-		Auth:    auth,
+		Host:     host,
+		Port:     port,
+		From:     data["from"].(string),
+		To:       to,
+		Subject:  data["subject"].(string),
+		Security: security,
+		AuthMode: data["authMode"].(string),
+		Auth:     auth,
 	}
 }
 
 func (n *EMailNotifier) SendNotification(service model.Service, message string) error {
-	return n.send(service, message)
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
 }
 
-func (n *EMailNotifier) send(service model.Service, message string) error {
-	header := make(map[string]string)
-	header["From"] = n.From
-	header["To"] = strings.Join(n.To, ", ")
-	header["Subject"] = n.Subject
-	header["MIME-version"] = "1.0"
-	header["Content-Type"] = "text/html; charset=\"utf-8\""
+// SendEvent sends the message as html and plain text mail. The subject template is rendered with the same data as
+// the message, digests use a fixed subject.
+func (n *EMailNotifier) SendEvent(event model.NotificationEvent) error {
+	subject := eventTitle(event)
+	if !event.IsDigest {
+		var err error
+		data := NewTemplateData(event.Service, event.IsUpNotification, event.Details, event.Date)
+		if subject, err = renderSubject(n.Subject, data); err != nil {
+			return err
+		}
+	}
+
+	message, err := buildMessage(n.From, n.To, subject, event.Message, event.Date)
+	if err != nil {
+		return err
+	}
+	return n.send(message)
+}
+
+// send delivers the message via implicit TLS, STARTTLS or plain SMTP depending on the security mode. In auto mode
+// STARTTLS is used if the server supports it.
+func (n *EMailNotifier) send(message []byte) error {
+	if err := checkEMailSecurity(n.Security); err != nil {
+		return fmt.Errorf("invalid security: %w", err)
+	}
+	if err := checkEMailAuthMode(n.AuthMode); err != nil {
+		return fmt.Errorf("invalid auth mode: %w", err)
+	}
 
-	eMailMessage := ""
+	address := net.JoinHostPort(n.Host, strconv.Itoa(n.Port))
+	tlsConfig := &tls.Config{ServerName: n.Host}
 
-	for k, v := range header {
-		eMailMessage += fmt.Sprintf("%s: %s\r\n", k, v)
+	var conn net.Conn
+	var err error
+	if n.Security == EMailSecurityTls {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: emailTimeout}, "tcp", address, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", address, emailTimeout)
+	}
+	if err != nil {
+		return err
 	}
 
-	eMailMessage += fmt.Sprintf("\r\n%s", message)
+	if err := conn.SetDeadline(time.Now().Add(emailTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
 
-	return smtp.SendMail(fmt.Sprintf("%s:%d", n.Host, n.Port), n.Auth, n.From, n.To, []byte(eMailMessage))
+	client, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if n.Security == EMailSecurityAuto || n.Security == EMailSecurityStartTls {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		} else if n.Security == EMailSecurityStartTls {
+			return fmt.Errorf("smtp server '%s' does not support STARTTLS", n.Host)
+		}
+	}
+
+	// like smtp.SendMail, relays that do not offer authentication are used without it
+	if ok, _ := client.Extension("AUTH"); ok && n.Auth != nil {
+		if err := client.Auth(n.Auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.From); err != nil {
+		return err
+	}
+
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
 }
 
 func (n *EMailNotifier) GetId() string {
@@ -180,3 +400,10 @@ func (n *EMailNotifier) GetServiceDownNotificationTemplate() string {
 	}
 	return defaultDownTemplate
 }
+
+func (n *EMailNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDigestTemplate
+}
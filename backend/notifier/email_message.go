@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/koloo91/monhttp/model"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var htmlLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</tr>|</h[1-6]>`)
+
+// renderSubject renders the subject template as plain text. Line breaks are removed so that the subject can not
+// add headers.
+func renderSubject(subjectTemplate string, data model.TemplateData) (string, error) {
+	tmpl, err := template.New("subject").Funcs(templateFuncs()).Parse(subjectTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(buffer.String()), " "), nil
+}
+
+// buildMessage returns a RFC 5322 message with a plain text and a html alternative of the html body. The headers
+// are written in a fixed order.
+func buildMessage(from string, to []string, subject, htmlBody string, date time.Time) ([]byte, error) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	if err := writeMessagePart(writer, "text/plain", htmlToText(htmlBody)); err != nil {
+		return nil, err
+	}
+	if err := writeMessagePart(writer, "text/html", htmlBody); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.New().String(), messageIdDomain(from))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=\"%s\"", writer.Boundary())},
+	}
+	for _, header := range headers {
+		message.WriteString(fmt.Sprintf("%s: %s\r\n", header[0], header[1]))
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writeMessagePart(writer *multipart.Writer, contentType, content string) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", contentType))
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// htmlToText turns the rendered html template into plain text, e.g. "Service <b>'Api'</b> is up<br>" into
+// "Service 'Api' is up".
+func htmlToText(value string) string {
+	value = htmlLineBreakRegex.ReplaceAllString(value, "\n")
+	value = htmlTagRegex.ReplaceAllString(value, "")
+	return strings.TrimSpace(html.UnescapeString(value))
+}
+
+// messageIdDomain returns the domain of the address or localhost.
+func messageIdDomain(address string) string {
+	address = strings.TrimSuffix(strings.TrimSpace(address), ">")
+	if index := strings.LastIndex(address, "@"); index >= 0 && index < len(address)-1 {
+		return address[index+1:]
+	}
+	return "localhost"
+}
+
+// loginAuth implements the LOGIN mechanism that is not part of net/smtp but required by some servers, e.g. Office
+// 365. Like smtp.PlainAuth it only sends the credentials over TLS or to localhost.
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge '%s'", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
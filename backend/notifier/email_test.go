@@ -0,0 +1,191 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMessageShouldWriteHeadersAndAlternatives(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+	message, err := buildMessage("monhttp@example.com", []string{"a@example.com", "b@example.com"}, "Api is down ⚠",
+		"Service <b>'Api'</b> is down<br>Reason: timeout &amp; more", date)
+	assert.Nil(t, err)
+
+	headerNames := make([]string, 0)
+	for _, line := range strings.Split(strings.SplitN(string(message), "\r\n\r\n", 2)[0], "\r\n") {
+		headerNames = append(headerNames, strings.SplitN(line, ":", 2)[0])
+	}
+	assert.Equal(t, []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type"}, headerNames)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(message)))
+	assert.Nil(t, err)
+	assert.Equal(t, "a@example.com, b@example.com", parsed.Header.Get("To"))
+	assert.Equal(t, "Sun, 20 Dec 2020 10:00:00 +0000", parsed.Header.Get("Date"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Api is down ⚠", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	text, err := reader.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, `text/plain; charset="utf-8"`, text.Header.Get("Content-Type"))
+	body, _ := ioutil.ReadAll(text)
+	assert.Equal(t, "Service 'Api' is down\r\nReason: timeout & more", string(body))
+
+	htmlPart, err := reader.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, `text/html; charset="utf-8"`, htmlPart.Header.Get("Content-Type"))
+	body, _ = ioutil.ReadAll(htmlPart)
+	assert.Equal(t, "Service <b>'Api'</b> is down<br>Reason: timeout &amp; more", string(body))
+}
+
+func TestRenderSubjectShouldRemoveLineBreaks(t *testing.T) {
+	subject, err := renderSubject(defaultEMailSubjectTemplate, model.TemplateData{Name: "Api\r\nBcc: x@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Api Bcc: x@example.com is down", subject)
+
+	subject, err = renderSubject("[{{if .IsUp}}OK{{else}}ALERT{{end}}] {{.Name}}", model.TemplateData{Name: "Api", IsUp: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "[OK] Api", subject)
+}
+
+func TestNewEMailNotifierShouldUseImplicitTlsOnPort465(t *testing.T) {
+	store := viper.New()
+	store.Set("NOTIFIER_EMAIL_PORT", 465)
+	assert.Equal(t, EMailSecurityTls, NewEMailNotifier(store).Security)
+
+	store.Set("NOTIFIER_EMAIL_PORT", 587)
+	assert.Equal(t, EMailSecurityAuto, NewEMailNotifier(store).Security)
+
+	store.Set("NOTIFIER_EMAIL_AUTHMODE", "none")
+	assert.Nil(t, NewEMailNotifier(store).Auth)
+}
+
+func TestEMailNotifierShouldNotSendWithUnknownSecurityOrAuthMode(t *testing.T) {
+	store := viper.New()
+	store.Set("NOTIFIER_EMAIL_HOST", "127.0.0.1")
+	store.Set("NOTIFIER_EMAIL_PORT", 1)
+	store.Set("NOTIFIER_EMAIL_SECURITY", "ssl")
+
+	err := NewEMailNotifier(store).SendNotification(model.Service{Name: "Api"}, "down")
+	assert.Equal(t, "invalid security: 'ssl' is not one of auto, tls, starttls or none", err.Error())
+
+	store.Set("NOTIFIER_EMAIL_SECURITY", "starttls")
+	store.Set("NOTIFIER_EMAIL_AUTHMODE", "cram")
+	notify := NewEMailNotifier(store)
+	assert.Nil(t, notify.Auth)
+
+	err = notify.SendNotification(model.Service{Name: "Api"}, "down")
+	assert.Equal(t, "invalid auth mode: 'cram' is not one of plain, login, cram-md5 or none", err.Error())
+}
+
+func TestEMailNotifierShouldSendWithLoginAuthAndSeparateUsername(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveFakeSmtp(listener, received)
+
+	store := viper.New()
+	store.Set("NOTIFIER_EMAIL_HOST", "127.0.0.1")
+	store.Set("NOTIFIER_EMAIL_PORT", listener.Addr().(*net.TCPAddr).Port)
+	store.Set("NOTIFIER_EMAIL_AUTHMODE", "login")
+	store.Set("NOTIFIER_EMAIL_USERNAME", "relay-user")
+	store.Set("NOTIFIER_EMAIL_PASSWORD", "secret")
+	store.Set("NOTIFIER_EMAIL_FROM", "monhttp@example.com")
+	store.Set("NOTIFIER_EMAIL_TO", "ops@example.com")
+	store.Set("NOTIFIER_EMAIL_SUBJECT", "[monhttp] {{.Name}} is {{if .IsUp}}up{{else}}down{{end}} ({{.StatusCode}}, {{.ConsecutiveFailures}} failures)")
+
+	err = NewEMailNotifier(store).SendEvent(model.NotificationEvent{
+		Service: model.Service{Name: "Api"},
+		Details: model.NotificationDetails{StatusCode: 503, ConsecutiveFailures: 3},
+		Message: "Service <b>'Api'</b> is down",
+		Date:    time.Now(),
+	})
+	assert.Nil(t, err)
+
+	commands := <-received
+	assert.Contains(t, commands, base64.StdEncoding.EncodeToString([]byte("relay-user")))
+	assert.Contains(t, commands, base64.StdEncoding.EncodeToString([]byte("secret")))
+	assert.Contains(t, commands, "MAIL FROM:<monhttp@example.com> BODY=8BITMIME")
+	assert.Contains(t, commands, "RCPT TO:<ops@example.com>")
+	assert.Contains(t, commands, "Subject: [monhttp] Api is down (503, 3 failures)")
+}
+
+// serveFakeSmtp accepts a single connection, offers AUTH LOGIN without STARTTLS and sends all received lines to the
+// channel once the client quits.
+func serveFakeSmtp(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	lines := make([]string, 0)
+	reply("220 localhost ESMTP")
+
+	inData := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		if inData {
+			if line == "." {
+				inData = false
+				reply("250 OK")
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			reply("250-localhost")
+			reply("250-8BITMIME")
+			reply("250 AUTH LOGIN")
+		case line == "AUTH LOGIN":
+			reply("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+			line, _ = reader.ReadString('\n')
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+			reply("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+			line, _ = reader.ReadString('\n')
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+			reply("235 Authentication successful")
+		case line == "DATA":
+			inData = true
+			reply("354 Start mail input")
+		case line == "QUIT":
+			reply("221 Bye")
+			received <- lines
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
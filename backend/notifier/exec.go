@@ -0,0 +1,362 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultExecTimeoutInSeconds = 30
+	// maxExecTimeoutInSeconds prevents a hanging command from blocking the delivery of other notifications for long
+	maxExecTimeoutInSeconds = 600
+	// maxExecOutputLength limits the output stored in the delivery log
+	maxExecOutputLength = 4096
+)
+
+// execInheritedVariables are the only variables of the server environment commands are run with
+var execInheritedVariables = []string{"PATH", "HOME", "LANG", "TZ"}
+
+var ErrExecNotifierDisabled = errors.New("exec notifier is disabled, set EXEC_NOTIFIER_ENABLED to true to enable it")
+
+// ExecNotifier runs a local command for every notification, e.g. to send a SMS via a custom gateway or to restart a
+// container. Commands are only run if EXEC_NOTIFIER_ENABLED is set.
+type ExecNotifier struct {
+	model.Notifier
+	Command          string
+	Arguments        []string
+	WorkingDirectory string
+	Timeout          time.Duration
+}
+
+func init() {
+	Register(NotifierType{
+		Type:     "exec",
+		New:      func(store *viper.Viper) model.Notify { return NewExecNotifier(store) },
+		Validate: validateExecData,
+	})
+}
+
+func NewExecNotifier(store *viper.Viper) *ExecNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_EXEC_ENABLED")
+	data["command"] = store.GetString("NOTIFIER_EXEC_COMMAND")
+	data["arguments"] = store.GetString("NOTIFIER_EXEC_ARGUMENTS")
+	data["workingDirectory"] = store.GetString("NOTIFIER_EXEC_WORKINGDIRECTORY")
+	data["timeoutInSeconds"] = store.GetInt("NOTIFIER_EXEC_TIMEOUTINSECONDS")
+
+	if value := data["timeoutInSeconds"].(int); value <= 0 {
+		data["timeoutInSeconds"] = defaultExecTimeoutInSeconds
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_EXEC_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTextUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_EXEC_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTextDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "exec", defaultTextDigestTemplate)
+
+	arguments := make([]string, 0)
+	for _, argument := range strings.Split(data["arguments"].(string), "\n") {
+		if argument = strings.TrimRight(argument, "\r"); len(argument) > 0 {
+			arguments = append(arguments, argument)
+		}
+	}
+
+	return &ExecNotifier{
+		Notifier: model.Notifier{
+			Id:      "exec",
+			Name:    "Exec",
+			Enabled: store.GetBool("NOTIFIER_EXEC_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Command",
+					FormControlName: "command",
+					Placeholder:     "/usr/local/bin/send-sms",
+					Required:        true,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Arguments, one per line",
+					FormControlName: "arguments",
+					Placeholder:     "--to\n+49123456789",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Working directory",
+					FormControlName: "workingDirectory",
+					Placeholder:     "/opt/monhttp",
+					Required:        false,
+				},
+				{
+					Type:            "number",
+					Title:           "Timeout in seconds",
+					FormControlName: "timeoutInSeconds",
+					Placeholder:     "30",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		Command:          data["command"].(string),
+		Arguments:        arguments,
+		WorkingDirectory: data["workingDirectory"].(string),
+		Timeout:          time.Duration(data["timeoutInSeconds"].(int)) * time.Second,
+	}
+}
+
+// validateExecData rejects exec notifiers unless they are enabled in the configuration, because they run commands
+// on the server.
+func validateExecData(_ model.Notify, data map[string]interface{}) ([]interface{}, error) {
+	if err := checkExecNotifierEnabled(); err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, 0)
+	if timeout, ok := toFormInt(data["timeoutInSeconds"]); ok && (timeout < 1 || timeout > maxExecTimeoutInSeconds) {
+		result = append(result, model.FieldErrorVo{
+			Field:   "timeoutInSeconds",
+			Message: fmt.Sprintf("must be between 1 and %d", maxExecTimeoutInSeconds),
+		})
+	}
+	return result, nil
+}
+
+func checkExecNotifierEnabled() error {
+	if !viper.GetBool("EXEC_NOTIFIER_ENABLED") {
+		return ErrExecNotifierDisabled
+	}
+	return nil
+}
+
+type execEvent struct {
+	Event   string      `json:"event"`
+	Service execService `json:"service"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+	Link    string      `json:"link"`
+	Date    time.Time   `json:"date"`
+}
+
+type execService struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     model.ServiceType `json:"type"`
+	Endpoint string            `json:"endpoint"`
+	Tags     []string          `json:"tags"`
+}
+
+func (n *ExecNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+func (n *ExecNotifier) SendEvent(event model.NotificationEvent) error {
+	_, err := n.SendEventWithOutput(event)
+	return err
+}
+
+// SendEventWithOutput runs the command with the event as JSON on stdin and as MONHTTP_* environment variables. It
+// returns the combined stdout and stderr of the command. A non-zero exit code or a timeout fail the delivery.
+func (n *ExecNotifier) SendEventWithOutput(event model.NotificationEvent) (string, error) {
+	if err := checkExecNotifierEnabled(); err != nil {
+		return "", err
+	}
+	if len(n.Command) == 0 {
+		return "", errors.New("exec notifier has no command")
+	}
+
+	payload := newExecEvent(event)
+	stdin, err := toJson(payload)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.Command, n.Arguments...)
+	cmd.Dir = n.WorkingDirectory
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(execEnvironment(),
+		"MONHTTP_EVENT="+payload.Event,
+		"MONHTTP_SERVICE_ID="+payload.Service.Id,
+		"MONHTTP_SERVICE_NAME="+payload.Service.Name,
+		"MONHTTP_SERVICE_TYPE="+string(payload.Service.Type),
+		"MONHTTP_SERVICE_ENDPOINT="+payload.Service.Endpoint,
+		"MONHTTP_REASON="+payload.Reason,
+		"MONHTTP_MESSAGE="+payload.Message,
+		"MONHTTP_LINK="+payload.Link,
+		"MONHTTP_DATE="+payload.Date.Format(time.RFC3339),
+	)
+
+	// a file instead of a buffer, so that children that inherited the output do not delay the timeout
+	output, err := ioutil.TempFile("", "monhttp-exec")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Run()
+	content, readErr := ioutil.ReadFile(output.Name())
+	if readErr != nil {
+		return "", readErr
+	}
+	result := truncateExecOutput(string(content))
+	if ctx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("command '%s' timed out after %s", n.Command, n.Timeout)
+	}
+	if err != nil {
+		return result, fmt.Errorf("command '%s' failed: %w", n.Command, err)
+	}
+	return result, nil
+}
+
+// execEnvironment returns the variables of the server environment the command inherits. The rest of the environment
+// is not passed, because the configuration, e.g. DATABASE_PASSWORD or the tokens of other notifiers, can be set via
+// environment variables.
+func execEnvironment() []string {
+	result := make([]string, 0, len(execInheritedVariables))
+	for _, name := range execInheritedVariables {
+		if value, exists := os.LookupEnv(name); exists {
+			result = append(result, name+"="+value)
+		}
+	}
+	return result
+}
+
+func newExecEvent(event model.NotificationEvent) execEvent {
+	state := "down"
+	switch {
+	case event.IsDigest:
+		state = "digest"
+	case event.IsUpNotification && event.IsDegraded:
+		state = "normal"
+	case event.IsUpNotification:
+		state = "up"
+	case event.IsDegraded:
+		state = "degraded"
+	}
+
+	date := event.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	return execEvent{
+		Event: state,
+		Service: execService{
+			Id:       event.Service.Id,
+			Name:     event.Service.Name,
+			Type:     event.Service.Type,
+			Endpoint: event.Service.Endpoint,
+			Tags:     event.Service.Tags,
+		},
+		Reason:  event.Failure.Reason,
+		Message: event.Message,
+		Link:    event.Link,
+		Date:    date,
+	}
+}
+
+// truncateExecOutput keeps the end of long outputs, which usually contains the error.
+func truncateExecOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxExecOutputLength {
+		return "..." + output[len(output)-maxExecOutputLength:]
+	}
+	return output
+}
+
+func (n *ExecNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *ExecNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *ExecNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *ExecNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *ExecNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *ExecNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *ExecNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextUpTemplate
+}
+
+func (n *ExecNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDownTemplate
+}
+
+func (n *ExecNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDigestTemplate
+}
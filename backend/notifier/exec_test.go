@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"encoding/json"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newShellNotifier(script string, timeoutInSeconds int) *ExecNotifier {
+	store := viper.New()
+	store.Set("NOTIFIER_EXEC_COMMAND", "/bin/sh")
+	store.Set("NOTIFIER_EXEC_ARGUMENTS", "-c\n"+script)
+	store.Set("NOTIFIER_EXEC_TIMEOUTINSECONDS", timeoutInSeconds)
+	return NewExecNotifier(store)
+}
+
+func TestExecNotifierShouldPassEventViaEnvironmentAndStdin(t *testing.T) {
+	viper.Set("EXEC_NOTIFIER_ENABLED", true)
+	defer viper.Set("EXEC_NOTIFIER_ENABLED", false)
+
+	notify := newShellNotifier(`echo "$MONHTTP_EVENT $MONHTTP_SERVICE_NAME $MONHTTP_REASON"; cat`, 0)
+	assert.Equal(t, []string{"-c", `echo "$MONHTTP_EVENT $MONHTTP_SERVICE_NAME $MONHTTP_REASON"; cat`}, notify.Arguments)
+
+	output, err := SendEventWithOutput(notify, newIncidentEvent(notify, false))
+	assert.Nil(t, err)
+
+	lines := strings.SplitN(output, "\n", 2)
+	assert.Equal(t, "down Api timeout", lines[0])
+
+	var event execEvent
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "down", event.Event)
+	assert.Equal(t, "2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10", event.Service.Id)
+	assert.Equal(t, "https://api.example.com/health", event.Service.Endpoint)
+	assert.Equal(t, "Service 'Api' is down. Reason: 'timeout' at ", event.Message)
+}
+
+func TestExecNotifierShouldNotPassServerEnvironment(t *testing.T) {
+	viper.Set("EXEC_NOTIFIER_ENABLED", true)
+	defer viper.Set("EXEC_NOTIFIER_ENABLED", false)
+	assert.Nil(t, os.Setenv("DATABASE_PASSWORD", "secret"))
+	defer os.Unsetenv("DATABASE_PASSWORD")
+
+	notify := newShellNotifier(`echo "password=$DATABASE_PASSWORD path=$PATH"`, 0)
+	output, err := SendEventWithOutput(notify, newIncidentEvent(notify, false))
+	assert.Nil(t, err)
+	assert.Equal(t, "password= path="+os.Getenv("PATH"), output)
+}
+
+func TestExecNotifierShouldFailOnNonZeroExitCodeAndTimeout(t *testing.T) {
+	viper.Set("EXEC_NOTIFIER_ENABLED", true)
+	defer viper.Set("EXEC_NOTIFIER_ENABLED", false)
+
+	notify := newShellNotifier("echo 'container not found' >&2; exit 3", 0)
+	output, err := SendEventWithOutput(notify, newIncidentEvent(notify, false))
+	assert.Equal(t, "container not found", output)
+	assert.Equal(t, "command '/bin/sh' failed: exit status 3", err.Error())
+
+	notify = newShellNotifier("echo started; sleep 5", 1)
+	output, err = SendEventWithOutput(notify, newIncidentEvent(notify, false))
+	assert.Equal(t, "started", output)
+	assert.Equal(t, "command '/bin/sh' timed out after 1s", err.Error())
+}
+
+func TestExecNotifierShouldNotRunCommandUnlessEnabled(t *testing.T) {
+	notify := newShellNotifier("echo ran", 0)
+
+	output, err := SendEventWithOutput(notify, newIncidentEvent(notify, false))
+	assert.Equal(t, "", output)
+	assert.Equal(t, ErrExecNotifierDisabled, err)
+}
@@ -0,0 +1,229 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGotifyUpPriority   = "2"
+	defaultGotifyDownPriority = "8"
+)
+
+type GotifyNotifier struct {
+	model.Notifier
+	ServerUrl    string
+	AppToken     string
+	UpPriority   string
+	DownPriority string
+	client       *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "gotify",
+		New:  func(store *viper.Viper) model.Notify { return NewGotifyNotifier(store) },
+	})
+}
+
+func NewGotifyNotifier(store *viper.Viper) *GotifyNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_GOTIFY_ENABLED")
+	data["serverUrl"] = store.GetString("NOTIFIER_GOTIFY_SERVERURL")
+	data["appToken"] = store.GetString("NOTIFIER_GOTIFY_APPTOKEN")
+	data["upPriority"] = store.GetString("NOTIFIER_GOTIFY_UPPRIORITY")
+	data["downPriority"] = store.GetString("NOTIFIER_GOTIFY_DOWNPRIORITY")
+
+	if value := data["upPriority"].(string); len(value) == 0 {
+		data["upPriority"] = defaultGotifyUpPriority
+	}
+
+	if value := data["downPriority"].(string); len(value) == 0 {
+		data["downPriority"] = defaultGotifyDownPriority
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_GOTIFY_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTextUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_GOTIFY_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTextDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "gotify", defaultTextDigestTemplate)
+
+	return &GotifyNotifier{
+		Notifier: model.Notifier{
+			Id:      "gotify",
+			Name:    "Gotify",
+			Enabled: store.GetBool("NOTIFIER_GOTIFY_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Server url",
+					FormControlName: "serverUrl",
+					Placeholder:     "https://gotify.example.com",
+					Required:        true,
+				},
+				{
+					Type:            "password",
+					Title:           "Application token",
+					FormControlName: "appToken",
+					Placeholder:     "AKFW8i.ed1YbQ9w",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Up priority",
+					FormControlName: "upPriority",
+					Placeholder:     "0 to 10",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Down priority",
+					FormControlName: "downPriority",
+					Placeholder:     "0 to 10",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		ServerUrl:    strings.TrimSuffix(data["serverUrl"].(string), "/"),
+		AppToken:     data["appToken"].(string),
+		UpPriority:   data["upPriority"].(string),
+		DownPriority: data["downPriority"].(string),
+		client:       &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+type gotifyMessage struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+func (n *GotifyNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent pushes the message to the application. Down notifications use the down priority, recoveries the up
+// priority.
+func (n *GotifyNotifier) SendEvent(event model.NotificationEvent) error {
+	title := eventTitle(event)
+	priorityValue := n.DownPriority
+	if event.IsUpNotification {
+		priorityValue = n.UpPriority
+	}
+
+	priority, err := strconv.Atoi(priorityValue)
+	if err != nil {
+		return fmt.Errorf("invalid gotify priority '%s'", priorityValue)
+	}
+
+	message := gotifyMessage{Title: title, Message: event.Message, Priority: priority}
+	if len(event.Link) > 0 {
+		message.Extras = map[string]interface{}{
+			"client::notification": map[string]interface{}{"click": map[string]string{"url": event.Link}},
+		}
+	}
+
+	body, err := toJson(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/message", n.ServerUrl), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Gotify-Key", n.AppToken)
+
+	return executeJsonRequest(n.client, request)
+}
+
+func (n *GotifyNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *GotifyNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *GotifyNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *GotifyNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *GotifyNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *GotifyNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *GotifyNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextUpTemplate
+}
+
+func (n *GotifyNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDownTemplate
+}
+
+func (n *GotifyNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDigestTemplate
+}
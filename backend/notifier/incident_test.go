@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordedRequest struct {
+	Method        string
+	Path          string
+	Query         string
+	Authorization string
+	Body          map[string]interface{}
+}
+
+func newIncidentStub(t *testing.T, requests *[]recordedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body map[string]interface{}
+		assert.Nil(t, json.NewDecoder(request.Body).Decode(&body))
+
+		*requests = append(*requests, recordedRequest{
+			Method:        request.Method,
+			Path:          request.URL.Path,
+			Query:         request.URL.RawQuery,
+			Authorization: request.Header.Get("Authorization"),
+			Body:          body,
+		})
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+func newIncidentEvent(notify model.Notify, isUp bool) model.NotificationEvent {
+	service := model.Service{Id: "2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10", Name: "Api", Endpoint: "https://api.example.com/health"}
+	data := model.TemplateData{Name: service.Name, Reason: "timeout"}
+
+	templateText := notify.GetServiceDownNotificationTemplate()
+	if isUp {
+		templateText = notify.GetServiceUpNotificationTemplate()
+	}
+	message, _ := RenderTemplate(notify, templateText, data)
+
+	return model.NotificationEvent{
+		Service:          service,
+		IsUpNotification: isUp,
+		Failure:          model.Failure{ServiceId: service.Id, Reason: data.Reason},
+		Message:          message,
+		Link:             "https://monhttp.example.com/services/" + service.Id,
+		Date:             time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPagerDutyNotifierShouldTriggerAndResolveWithSameDedupKey(t *testing.T) {
+	var requests []recordedRequest
+	server := newIncidentStub(t, &requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_PAGERDUTY_ROUTINGKEY", "routing-key")
+	store.Set("NOTIFIER_PAGERDUTY_APIURL", server.URL+"/")
+	pagerDuty := NewPagerDutyNotifier(store)
+
+	assert.Nil(t, SendEvent(pagerDuty, newIncidentEvent(pagerDuty, false)))
+	assert.Nil(t, SendEvent(pagerDuty, newIncidentEvent(pagerDuty, true)))
+	assert.Equal(t, 2, len(requests))
+
+	trigger := requests[0]
+	assert.Equal(t, "/v2/enqueue", trigger.Path)
+	assert.Equal(t, "routing-key", trigger.Body["routing_key"])
+	assert.Equal(t, "trigger", trigger.Body["event_action"])
+	assert.Equal(t, "monhttp-2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10", trigger.Body["dedup_key"])
+
+	payload := trigger.Body["payload"].(map[string]interface{})
+	assert.Equal(t, "Service 'Api' is down: timeout", payload["summary"])
+	assert.Equal(t, "api.example.com", payload["source"])
+	assert.Equal(t, "critical", payload["severity"])
+	assert.Equal(t, "2020-12-20T10:00:00Z", payload["timestamp"])
+	assert.Equal(t, "timeout", payload["custom_details"].(map[string]interface{})["reason"])
+	assert.Equal(t, "https://monhttp.example.com/services/2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10",
+		trigger.Body["links"].([]interface{})[0].(map[string]interface{})["href"])
+
+	resolve := requests[1]
+	assert.Equal(t, "/v2/enqueue", resolve.Path)
+	assert.Equal(t, "resolve", resolve.Body["event_action"])
+	assert.Equal(t, trigger.Body["dedup_key"], resolve.Body["dedup_key"])
+	assert.Nil(t, resolve.Body["payload"])
+}
+
+func TestOpsgenieNotifierShouldCreateAndCloseAlertByAlias(t *testing.T) {
+	var requests []recordedRequest
+	server := newIncidentStub(t, &requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_OPSGENIE_APIKEY", "api-key")
+	store.Set("NOTIFIER_OPSGENIE_PRIORITY", "P2")
+	store.Set("NOTIFIER_OPSGENIE_APIURL", server.URL)
+	opsgenie := NewOpsgenieNotifier(store)
+
+	assert.Nil(t, SendEvent(opsgenie, newIncidentEvent(opsgenie, false)))
+	assert.Nil(t, SendEvent(opsgenie, newIncidentEvent(opsgenie, true)))
+	assert.Equal(t, 2, len(requests))
+
+	create := requests[0]
+	assert.Equal(t, http.MethodPost, create.Method)
+	assert.Equal(t, "/v2/alerts", create.Path)
+	assert.Equal(t, "GenieKey api-key", create.Authorization)
+	assert.Equal(t, "Service 'Api' is down", create.Body["message"])
+	assert.Equal(t, "monhttp-2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10", create.Body["alias"])
+	assert.Equal(t, "timeout", create.Body["description"])
+	assert.Equal(t, "P2", create.Body["priority"])
+
+	closeAlert := requests[1]
+	assert.Equal(t, "/v2/alerts/monhttp-2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10/close", closeAlert.Path)
+	assert.Equal(t, "identifierType=alias", closeAlert.Query)
+	assert.Equal(t, "GenieKey api-key", closeAlert.Authorization)
+	assert.Equal(t, "Service 'Api' is up again", closeAlert.Body["note"])
+}
+
+func TestSendEventShouldPassRenderedMessageToPlainNotifiers(t *testing.T) {
+	var receivedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Nil(t, json.NewDecoder(request.Body).Decode(&receivedBody))
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhookNotifier(server.URL, nil)
+	assert.Nil(t, SendEvent(webhook, model.NotificationEvent{Message: `{"event": "down"}`}))
+	assert.Equal(t, "down", receivedBody["event"])
+}
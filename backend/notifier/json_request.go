@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	jsonRequestTimeout = 10 * time.Second
+)
+
+// renderJsonTemplate renders a json payload with text/template. Values should be inserted with the json function
+// so that they are quoted and escaped correctly.
+func renderJsonTemplate(name, text string, data model.TemplateData) (string, error) {
+	funcs := templateFuncs()
+	funcs["json"] = toJson
+
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func toJson(value interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// postJson sends the body to an incoming webhook url.
+func postJson(client *http.Client, url, body string) error {
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	return executeJsonRequest(client, request)
+}
+
+// executeJsonRequest sends the request and treats every status code outside of 2xx as error.
+func executeJsonRequest(client *http.Client, request *http.Request) error {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		contentBytes, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("'%s' responded with status code '%d': '%s'", request.URL.Host, response.StatusCode, string(contentBytes))
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// m.notice is meant for bots and does not trigger notifications in most clients
+	defaultMatrixUpMessageType   = "m.notice"
+	defaultMatrixDownMessageType = "m.text"
+)
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+type MatrixNotifier struct {
+	model.Notifier
+	HomeserverUrl   string
+	AccessToken     string
+	RoomId          string
+	UpMessageType   string
+	DownMessageType string
+	client          *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "matrix",
+		New:  func(store *viper.Viper) model.Notify { return NewMatrixNotifier(store) },
+	})
+}
+
+func NewMatrixNotifier(store *viper.Viper) *MatrixNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_MATRIX_ENABLED")
+	data["homeserverUrl"] = store.GetString("NOTIFIER_MATRIX_HOMESERVERURL")
+	data["accessToken"] = store.GetString("NOTIFIER_MATRIX_ACCESSTOKEN")
+	data["roomId"] = store.GetString("NOTIFIER_MATRIX_ROOMID")
+	data["upMessageType"] = store.GetString("NOTIFIER_MATRIX_UPMESSAGETYPE")
+	data["downMessageType"] = store.GetString("NOTIFIER_MATRIX_DOWNMESSAGETYPE")
+
+	if value := data["upMessageType"].(string); len(value) == 0 {
+		data["upMessageType"] = defaultMatrixUpMessageType
+	}
+
+	if value := data["downMessageType"].(string); len(value) == 0 {
+		data["downMessageType"] = defaultMatrixDownMessageType
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_MATRIX_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_MATRIX_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "matrix", defaultDigestTemplate)
+
+	return &MatrixNotifier{
+		Notifier: model.Notifier{
+			Id:      "matrix",
+			Name:    "Matrix",
+			Enabled: store.GetBool("NOTIFIER_MATRIX_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Homeserver url",
+					FormControlName: "homeserverUrl",
+					Placeholder:     "https://matrix.example.com",
+					Required:        true,
+				},
+				{
+					Type:            "password",
+					Title:           "Access token",
+					FormControlName: "accessToken",
+					Placeholder:     "syt_bW9uaHR0cA_XXXXXXXXXXXXXXXXXXXX_0000",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Room id",
+					FormControlName: "roomId",
+					Placeholder:     "!qporfwt:matrix.example.com",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Up message type",
+					FormControlName: "upMessageType",
+					Placeholder:     "m.notice (silent) or m.text",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Down message type",
+					FormControlName: "downMessageType",
+					Placeholder:     "m.text or m.notice (silent)",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		HomeserverUrl:   strings.TrimSuffix(data["homeserverUrl"].(string), "/"),
+		AccessToken:     data["accessToken"].(string),
+		RoomId:          data["roomId"].(string),
+		UpMessageType:   data["upMessageType"].(string),
+		DownMessageType: data["downMessageType"].(string),
+		client:          &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+type matrixMessage struct {
+	MessageType   string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+func (n *MatrixNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent posts the html message into the room. Down notifications use the down message type, recoveries the up
+// message type.
+func (n *MatrixNotifier) SendEvent(event model.NotificationEvent) error {
+	messageType := n.DownMessageType
+	if event.IsUpNotification {
+		messageType = n.UpMessageType
+	}
+
+	formattedBody := event.Message
+	if len(event.Link) > 0 {
+		formattedBody = fmt.Sprintf(`%s <a href="%s">Open in monhttp</a>`, formattedBody, html.EscapeString(event.Link))
+	}
+
+	body, err := toJson(matrixMessage{
+		MessageType:   messageType,
+		Body:          html.UnescapeString(htmlTagRegex.ReplaceAllString(formattedBody, "")),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: formattedBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the homeserver ignores a transaction id it has already seen, so retries of a queued notification are not
+	// posted twice. Test messages are not queued and get a new id
+	transactionId := event.NotificationId
+	if len(transactionId) == 0 {
+		transactionId = uuid.New().String()
+	}
+	requestUrl := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		n.HomeserverUrl, url.PathEscape(n.RoomId), url.PathEscape(transactionId))
+
+	request, err := http.NewRequest(http.MethodPut, requestUrl, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+n.AccessToken)
+
+	return executeJsonRequest(n.client, request)
+}
+
+func (n *MatrixNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *MatrixNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *MatrixNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *MatrixNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *MatrixNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *MatrixNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultUpTemplate
+}
+
+func (n *MatrixNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDownTemplate
+}
+
+func (n *MatrixNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultDigestTemplate
+}
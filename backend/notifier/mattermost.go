@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+const (
+	defaultMattermostUpTemplate     = `{"attachments": [{"fallback": {{if .IsDegraded}}{{json (printf "Latency of %s is back to normal" .Name)}}{{else}}{{json (printf "%s is up again" .Name)}}{{end}}, "color": "#2eb886", "title": {{if .IsDegraded}}{{json (printf "Latency of %s is back to normal" .Name)}}{{else}}{{json (printf "%s is up again" .Name)}}{{end}}, {{if .Link}}"title_link": {{json .Link}}, {{end}}"footer": {{json .Date}}}]}`
+	defaultMattermostDownTemplate   = `{"attachments": [{"fallback": {{if .IsDegraded}}{{json (printf "%s is degraded" .Name)}}{{else}}{{json (printf "%s is down" .Name)}}{{end}}, "color": "#e01e5a", "title": {{if .IsDegraded}}{{json (printf "%s is degraded" .Name)}}{{else}}{{json (printf "%s is down" .Name)}}{{end}}, {{if .Link}}"title_link": {{json .Link}}, {{end}}"text": {{json (printf "Reason: %s" .Reason)}}, "footer": {{json .Date}}}]}`
+	defaultMattermostDigestTemplate = `{"attachments": [{"fallback": {{json (printf "%d services down, %d recovered" .DownCount .UpCount)}}, "color": "#ecb22e", "title": {{json (printf "%d services down, %d recovered" .DownCount .UpCount)}}, "fields": [{{range $i, $e := .Down}}{{if $i}}, {{end}}{"title": {{if $e.IsDegraded}}{{json (printf "%s is degraded" $e.Name)}}{{else}}{{json (printf "%s is down" $e.Name)}}{{end}}, "value": {{json (printf "Reason: %s" $e.Reason)}}}{{end}}{{if and .Down .Up}}, {{end}}{{range $i, $e := .Up}}{{if $i}}, {{end}}{"title": {{if $e.IsDegraded}}{{json (printf "Latency of %s is back to normal" $e.Name)}}{{else}}{{json (printf "%s is up again" $e.Name)}}{{end}}, "value": {{json $e.Date}}}{{end}}], "footer": {{json .Date}}}]}`
+)
+
+type MattermostNotifier struct {
+	model.Notifier
+	WebhookUrl string
+	client     *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "mattermost",
+		New:  func(store *viper.Viper) model.Notify { return NewMattermostNotifier(store) },
+	})
+}
+
+func NewMattermostNotifier(store *viper.Viper) *MattermostNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_MATTERMOST_ENABLED")
+	data["webhookUrl"] = store.GetString("NOTIFIER_MATTERMOST_WEBHOOKURL")
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_MATTERMOST_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultMattermostUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_MATTERMOST_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultMattermostDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "mattermost", defaultMattermostDigestTemplate)
+
+	return &MattermostNotifier{
+		Notifier: model.Notifier{
+			Id:      "mattermost",
+			Name:    "Mattermost",
+			Enabled: store.GetBool("NOTIFIER_MATTERMOST_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Incoming webhook url",
+					FormControlName: "webhookUrl",
+					Placeholder:     "https://mattermost.example.com/hooks/xxxxxxxxxxxxxxxxxxxxxxxxxx",
+					Required:        true,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Attachment payload for {{.Name}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Attachment payload for {{.Name}} and {{.Reason}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		WebhookUrl: store.GetString("NOTIFIER_MATTERMOST_WEBHOOKURL"),
+		client:     &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *MattermostNotifier) SendNotification(service model.Service, message string) error {
+	return postJson(n.client, n.WebhookUrl, message)
+}
+
+func (n *MattermostNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *MattermostNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *MattermostNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *MattermostNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *MattermostNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *MattermostNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *MattermostNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultMattermostUpTemplate
+}
+
+func (n *MattermostNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultMattermostDownTemplate
+}
+
+func (n *MattermostNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultMattermostDigestTemplate
+}
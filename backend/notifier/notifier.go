@@ -3,77 +3,97 @@ package notifier
 import (
 	"bytes"
 	"fmt"
-	"github.com/fsnotify/fsnotify"
-	"github.com/google/uuid"
 	"github.com/koloo91/monhttp/model"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"html/template"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	defaultUpTemplate   = "Service <b>'{{.Name}}'</b> is up again!"
-	defaultDownTemplate = "Service <b>'{{.Name}}'</b> is down. Reason: '{{.Reason}}' at {{.Date}}"
+	defaultUpTemplate   = "{{if .IsDegraded}}Latency of service <b>'{{.Name}}'</b> is back to normal.{{else}}Service <b>'{{.Name}}'</b> is up again!{{end}}{{if .Downtime}} It was down for {{duration .Downtime}}.{{end}}"
+	defaultDownTemplate = "Service <b>'{{.Name}}'</b> is {{if .IsDegraded}}degraded{{else}}down{{end}}. Reason: '{{.Reason}}' at {{.Date}}"
+
+	defaultTextUpTemplate   = "{{if .IsDegraded}}Latency of service '{{.Name}}' is back to normal.{{else}}Service '{{.Name}}' is up again!{{end}}{{if .Downtime}} It was down for {{duration .Downtime}}.{{end}}"
+	defaultTextDownTemplate = "Service '{{.Name}}' is {{if .IsDegraded}}degraded{{else}}down{{end}}. Reason: '{{.Reason}}' at {{.Date}}"
 
 	globalNotifierId = "global"
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
 )
 
 type NotificationSystem struct {
-	notifiers         []model.Notify
-	notificationQueue chan Notification
+	mutex     sync.RWMutex
+	notifiers []model.Notify
 }
 
 func NewNotificationSystem() *NotificationSystem {
 	return &NotificationSystem{
-		notifiers:         make([]model.Notify, 0),
-		notificationQueue: make(chan Notification, 1024),
-	}
-}
-
-func (n *NotificationSystem) SetupDefaultNotifier() {
-	log.Info("Setting up default notifiers")
-	n.notifiers = make([]model.Notify, 0)
-
-	load := func() {
-		log.Info("Adding notifiers")
-		n.notifiers = append(n.notifiers, NewEMailNotifier(viper.GetViper()))
-		n.notifiers = append(n.notifiers, NewTelegramNotifier(viper.GetViper()))
-	}
-	load()
-
-	viper.OnConfigChange(func(in fsnotify.Event) {
-		log.Info("Config file changed: ", in.Name)
-		// reset notifiers
-		n.notifiers = make([]model.Notify, 0)
-		load()
-	})
-}
-
-func (n *NotificationSystem) Start() {
-	go func() {
-		for notification := range n.notificationQueue {
-			if hasGlobalNotifierSet(notification.Service.Notifiers) {
-				for _, notifier := range n.getEnabledNotifiers() {
-					if err := sendNotification(notifier, notification); err != nil {
-						log.Errorf("Unable to send notification: '%s' - '%s'", notifier.GetId(), err)
-					}
-				}
-			} else {
-				for _, notifierId := range notification.Service.Notifiers {
-					notifier, err := n.getNotifierById(notifierId)
-					if err != nil {
-						log.Error(err)
-						continue
-					}
-
-					if err := sendNotification(notifier, notification); err != nil {
-						log.Errorf("Unable to send notification: '%s' - '%s'", notifier.GetId(), err)
-					}
-				}
-			}
+		notifiers: make([]model.Notify, 0),
+	}
+}
+
+// SetNotifiers replaces the notifier instances notifications are sent with.
+func (n *NotificationSystem) SetNotifiers(notifiers []model.Notify) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.notifiers = notifiers
+}
+
+// NewStore puts the form values of a notifier into a store with the keys the constructor of the type reads.
+func NewStore(notifierType string, data map[string]interface{}) *viper.Viper {
+	store := viper.New()
+	for k, v := range data {
+		store.Set(fmt.Sprintf("NOTIFIER_%s_%s", strings.ToUpper(notifierType), strings.ToUpper(k)), v)
+	}
+	return store
+}
+
+type instanceNotifier interface {
+	SetInstance(id, name string)
+	SetRateLimit(rateLimit int)
+	SetSchedule(schedule *model.NotificationSchedule)
+}
+
+// NewNotifier creates the notifier of a persisted instance.
+func NewNotifier(instance model.NotifierInstance) (model.Notify, error) {
+	notify, err := NewNotifierOfType(instance.Type, NewStore(instance.Type, instance.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	configurable, ok := notify.(instanceNotifier)
+	if !ok {
+		return nil, fmt.Errorf("notifier type '%s' does not embed model.Notifier", instance.Type)
+	}
+
+	configurable.SetInstance(instance.Id, instance.Name)
+	configurable.SetRateLimit(instance.RateLimit)
+	configurable.SetSchedule(instance.Schedule)
+	return notify, nil
+}
+
+// GetRecipients returns the notifiers a notification of a service is sent with. The global notifier id stands for
+// all enabled notifiers.
+func (n *NotificationSystem) GetRecipients(notifierIds []string) []model.Notify {
+	if hasGlobalNotifierSet(notifierIds) {
+		return n.getEnabledNotifiers()
+	}
+
+	result := make([]model.Notify, 0, len(notifierIds))
+	for _, notifierId := range notifierIds {
+		notifier, err := n.GetNotifierById(notifierId)
+		if err != nil {
+			log.Error(err)
+			continue
 		}
-	}()
+		result = append(result, notifier)
+	}
+	return result
 }
 
 func hasGlobalNotifierSet(notifierIds []string) bool {
@@ -85,42 +105,134 @@ func hasGlobalNotifierSet(notifierIds []string) bool {
 	return false
 }
 
-func sendNotification(notifier model.Notify, notification Notification) error {
-	log.Infof("Sending notification using '%s' notifier", notifier.GetId())
+// RenderNotification renders the up or down template of the notifier for the service. A template override of the
+// service takes precedence over the template of the notifier.
+func RenderNotification(notifier model.Notify, service model.Service, isUpNotification bool, details model.NotificationDetails,
+	date time.Time) (string, error) {
+	templateText := service.NotificationTemplate(notifier.GetId(), isUpNotification)
+	if len(templateText) == 0 && isUpNotification {
+		templateText = notifier.GetServiceUpNotificationTemplate()
+	} else if len(templateText) == 0 {
+		templateText = notifier.GetServiceDownNotificationTemplate()
+	}
 
-	var tmpl *template.Template
-	var err error
+	return RenderTemplate(notifier, templateText, NewTemplateData(service, isUpNotification, details, date))
+}
 
-	if notification.IsUpNotification {
-		tmpl, err = template.New(notifier.GetId()).Parse(notifier.GetServiceUpNotificationTemplate())
-	} else {
-		tmpl, err = template.New(notifier.GetId()).Parse(notifier.GetServiceDownNotificationTemplate())
+// NewTemplateData returns the template data of an up or down notification. The downtime is only set for up
+// notifications of an outage with a known start.
+func NewTemplateData(service model.Service, isUpNotification bool, details model.NotificationDetails, date time.Time) model.TemplateData {
+	data := model.TemplateData{
+		IsUp:                isUpNotification,
+		IsDegraded:          details.IsDegraded,
+		Name:                service.Name,
+		Date:                date.Format(time.RFC3339),
+		Reason:              details.Reason,
+		Link:                ServiceLink(service.Id),
+		Endpoint:            service.Endpoint,
+		Type:                service.Type,
+		StatusCode:          details.StatusCode,
+		LatencyInMs:         details.LatencyInMs,
+		ConsecutiveFailures: details.ConsecutiveFailures,
 	}
 
-	if err != nil {
-		log.Errorf("Unable to parse template for notifier '%s' - '%s'", notifier.GetId(), err)
-		return err
+	if details.DownSince != nil {
+		data.DownSince = details.DownSince.Format(time.RFC3339)
+		if isUpNotification {
+			data.Downtime = date.Sub(*details.DownSince)
+		}
 	}
+	return data
+}
 
-	data := model.TemplateData{
-		Name:   notification.Service.Name,
-		Date:   time.Now().Format(time.RFC3339),
-		Reason: notification.Failure.Reason,
+// RetryDelay returns the delay before the next delivery attempt. It doubles with every failed attempt.
+func RetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// SendEvent passes the event to notifiers implementing model.EventNotifier and the rendered message to all others.
+func SendEvent(notifier model.Notify, event model.NotificationEvent) error {
+	if eventNotifier, ok := notifier.(model.EventNotifier); ok {
+		return eventNotifier.SendEvent(event)
+	}
+	return notifier.SendNotification(event.Service, event.Message)
+}
+
+// SendEventWithOutput passes the event to notifiers implementing model.OutputNotifier and returns their output. All
+// other notifiers have no output.
+func SendEventWithOutput(notifier model.Notify, event model.NotificationEvent) (string, error) {
+	if outputNotifier, ok := notifier.(model.OutputNotifier); ok {
+		return outputNotifier.SendEventWithOutput(event)
+	}
+	return "", SendEvent(notifier, event)
+}
+
+// incidentKey is the stable key used by incident management notifiers to deduplicate and resolve the incident of
+// a service.
+func incidentKey(serviceId string) string {
+	return "monhttp-" + serviceId
+}
+
+// serviceSource returns the host of the service endpoint or the service name if the endpoint is not an url.
+func serviceSource(service model.Service) string {
+	if endpoint, err := url.Parse(service.Endpoint); err == nil && len(endpoint.Host) > 0 {
+		return endpoint.Host
+	}
+	if len(service.Endpoint) > 0 {
+		return service.Endpoint
+	}
+	return service.Name
+}
+
+func truncate(value string, maxLength int) string {
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength])
+}
+
+// ServiceLink returns the url of the service page based on PUBLIC_URL or an empty string if it is not configured.
+func ServiceLink(serviceId string) string {
+	publicUrl := strings.TrimSuffix(viper.GetString("PUBLIC_URL"), "/")
+	if len(publicUrl) == 0 || len(serviceId) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/services/%s", publicUrl, serviceId)
+}
+
+// RenderTemplate renders the template text with the notifiers own renderer or with html/template.
+func RenderTemplate(notifier model.Notify, templateText string, data model.TemplateData) (string, error) {
+	if renderer, ok := notifier.(model.TemplateRenderer); ok {
+		return renderer.RenderTemplate(notifier.GetId(), templateText, data)
+	}
+
+	tmpl, err := template.New(notifier.GetId()).Funcs(templateFuncs()).Parse(templateText)
+	if err != nil {
+		log.Errorf("Unable to parse template for notifier '%s' - '%s'", notifier.GetId(), err)
+		return "", err
 	}
 
 	var buffer bytes.Buffer
 	if err := tmpl.Execute(&buffer, data); err != nil {
 		log.Errorf("Unable to execute template for notifier '%s' - '%s'", notifier.GetId(), err)
-		return err
+		return "", err
 	}
 
-	if err := notifier.SendNotification(notification.Service, buffer.String()); err != nil {
-		log.Errorf("Unable to send notification with notifier '%s' - '%s'", notifier.GetId(), err)
-	}
-	return nil
+	return buffer.String(), nil
 }
 
 func (n *NotificationSystem) getEnabledNotifiers() []model.Notify {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
 	result := make([]model.Notify, 0, len(n.notifiers))
 	for _, notifier := range n.notifiers {
 		if notifier.IsEnabled() {
@@ -130,7 +242,10 @@ func (n *NotificationSystem) getEnabledNotifiers() []model.Notify {
 	return result
 }
 
-func (n *NotificationSystem) getNotifierById(id string) (model.Notify, error) {
+func (n *NotificationSystem) GetNotifierById(id string) (model.Notify, error) {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
 	for _, notifier := range n.notifiers {
 		if notifier.GetId() == id {
 			return notifier, nil
@@ -139,31 +254,9 @@ func (n *NotificationSystem) getNotifierById(id string) (model.Notify, error) {
 	return nil, fmt.Errorf("notifier with id '%s' not found", id)
 }
 
-func (n *NotificationSystem) AddNotification(notification Notification) {
-	go func() {
-		logFields := log.WithFields(log.Fields{"serviceId": notification.Service.Id, "isUpNotification": notification.IsUpNotification})
-		logFields.Infof("Adding notification to notification queu")
-		// add notification non blocking
-		n.notificationQueue <- notification
-	}()
-}
-
 func (n *NotificationSystem) GetNotifiers() []model.Notify {
-	return n.notifiers
-}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
 
-type Notification struct {
-	Id               string
-	Service          model.Service
-	IsUpNotification bool
-	Failure          model.Failure
-}
-
-func NewNotification(service model.Service, isUpNotification bool, failure model.Failure) Notification {
-	return Notification{
-		Id:               uuid.New().String(),
-		Service:          service,
-		IsUpNotification: isUpNotification,
-		Failure:          failure,
-	}
+	return n.notifiers
 }
@@ -0,0 +1,168 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNewNotifierShouldUseIdAndNameOfInstance(t *testing.T) {
+	notify, err := NewNotifier(model.NotifierInstance{
+		Id:   "3b0f5ad4-5b4e-4f4a-9d2c-0b7c3f0e8f11",
+		Type: "telegram",
+		Name: "Team A",
+		Data: map[string]interface{}{"enabled": true, "channel": "4711"},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "3b0f5ad4-5b4e-4f4a-9d2c-0b7c3f0e8f11", notify.GetId())
+	assert.Equal(t, "telegram", notify.GetType())
+	assert.Equal(t, "Team A", notify.GetName())
+	assert.True(t, notify.IsEnabled())
+	assert.Equal(t, "4711", notify.(*TelegramNotifier).Channel)
+}
+
+func TestNewNotifierShouldReturnErrorForUnknownType(t *testing.T) {
+	_, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "carrier-pigeon"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "notifier type 'carrier-pigeon' is unknown", err.Error())
+}
+
+func TestTypesShouldAllBeCreatable(t *testing.T) {
+	for _, notifierType := range Types() {
+		notify, err := NewNotifierOfType(notifierType, NewStore(notifierType, nil))
+		assert.Nil(t, err, notifierType)
+		assert.Equal(t, notifierType, notify.GetType())
+	}
+}
+
+func TestTypesShouldBeSorted(t *testing.T) {
+	types := Types()
+	assert.True(t, sort.StringsAreSorted(types))
+	assert.Contains(t, types, "email")
+	assert.Contains(t, types, "exec")
+}
+
+func TestRegisterShouldPanicForDuplicateType(t *testing.T) {
+	assert.PanicsWithValue(t, "notifier type 'webhook' is registered twice", func() {
+		Register(NotifierType{Type: "webhook", New: func(store *viper.Viper) model.Notify { return NewWebhookNotifier(store) }})
+	})
+}
+
+func TestRetryDelayShouldDoubleUpToMaximum(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 2*time.Minute, RetryDelay(3))
+	assert.Equal(t, 16*time.Minute, RetryDelay(6))
+	assert.Equal(t, time.Hour, RetryDelay(8))
+	assert.Equal(t, time.Hour, RetryDelay(100))
+}
+
+func TestRenderNotificationShouldPreferTemplateOfService(t *testing.T) {
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "telegram", Name: "Team A"})
+	assert.Nil(t, err)
+
+	service := model.Service{
+		Name: "Api",
+		NotificationTemplates: []model.NotificationTemplate{
+			{NotifierId: "2", DownTemplate: "Other notifier"},
+			{NotifierId: "1", DownTemplate: "{{.Name}} of team A is down: {{.Reason}}"},
+		},
+	}
+	details := model.NotificationDetails{Reason: "timeout"}
+
+	message, err := RenderNotification(notify, service, false, details, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, "Api of team A is down: timeout", message)
+
+	message, err = RenderNotification(notify, service, true, details, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, "Service <b>'Api'</b> is up again!", message)
+}
+
+func TestNewNotifierShouldUseScheduleOfInstance(t *testing.T) {
+	schedule := &model.NotificationSchedule{
+		TimeZone:     "Europe/Berlin",
+		Days:         []string{"MON", "TUE", "WED", "THU", "FRI"},
+		StartTime:    "22:00",
+		EndTime:      "06:00",
+		CriticalTags: []string{"production"},
+	}
+
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "email", Name: "On call", Schedule: schedule})
+	assert.Nil(t, err)
+	assert.Equal(t, schedule, notify.GetSchedule())
+
+	// Friday 23:00 and Saturday 05:00 in Berlin belong to the window starting on Friday
+	assert.True(t, schedule.IsActive(time.Date(2020, 12, 18, 22, 0, 0, 0, time.UTC)))
+	assert.True(t, schedule.IsActive(time.Date(2020, 12, 19, 4, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsActive(time.Date(2020, 12, 19, 5, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsActive(time.Date(2020, 12, 19, 22, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsActive(time.Date(2020, 12, 21, 12, 0, 0, 0, time.UTC)))
+
+	assert.True(t, schedule.IsCritical(model.Service{Tags: []string{"Production"}}))
+	assert.False(t, schedule.IsCritical(model.Service{Tags: []string{"staging"}}))
+}
+
+func TestRenderNotificationShouldRenderDegradedState(t *testing.T) {
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "telegram", Name: "Team A"})
+	assert.Nil(t, err)
+
+	details := model.NotificationDetails{Reason: "p95 of the last 10 checks is 1200ms", IsDegraded: true}
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+
+	message, err := RenderNotification(notify, model.Service{Name: "Api"}, false, details, date)
+	assert.Nil(t, err)
+	assert.Equal(t, "Service <b>'Api'</b> is degraded. Reason: 'p95 of the last 10 checks is 1200ms' at 2020-12-20T10:00:00Z", message)
+
+	event := model.NotificationEvent{Service: model.Service{Name: "Api"}, IsDegraded: true}
+	assert.Equal(t, "Api is degraded", eventTitle(event))
+}
+
+func TestRenderNotificationShouldNotRenderLatencyRecoveryAsUp(t *testing.T) {
+	details := model.NotificationDetails{Reason: "Latency is back to normal", IsDegraded: true}
+	date := time.Date(2020, 12, 20, 10, 0, 0, 0, time.UTC)
+
+	for _, notify := range append(newTestChatNotifiers(""), NewWebhookNotifier(viper.New()), NewPagerDutyNotifier(viper.New()),
+		NewOpsgenieNotifier(viper.New())) {
+		message, err := RenderNotification(notify, model.Service{Name: "Api"}, true, details, date)
+		assert.Nil(t, err, notify.GetId())
+		assert.NotContains(t, message, "up again", notify.GetId())
+		assert.NotContains(t, message, `"up"`, notify.GetId())
+
+		message, err = RenderNotification(notify, model.Service{Name: "Api"}, false, details, date)
+		assert.Nil(t, err, notify.GetId())
+		assert.NotContains(t, message, "is down", notify.GetId())
+	}
+
+	event := model.NotificationEvent{Service: model.Service{Name: "Api"}, IsUpNotification: true, IsDegraded: true}
+	assert.Equal(t, "Api is back to normal", eventTitle(event))
+}
+
+// bareNotifier implements model.Notify without embedding model.Notifier
+type bareNotifier struct{}
+
+func (n *bareNotifier) GetId() string                                { return "bare" }
+func (n *bareNotifier) GetType() string                              { return "bare" }
+func (n *bareNotifier) GetRateLimit() int                            { return 0 }
+func (n *bareNotifier) GetSchedule() *model.NotificationSchedule     { return nil }
+func (n *bareNotifier) SendNotification(model.Service, string) error { return nil }
+func (n *bareNotifier) IsEnabled() bool                              { return true }
+func (n *bareNotifier) GetForms() []model.NotificationForm           { return nil }
+func (n *bareNotifier) GetName() string                              { return "Bare" }
+func (n *bareNotifier) GetData() map[string]interface{}              { return nil }
+func (n *bareNotifier) GetServiceUpNotificationTemplate() string     { return "" }
+func (n *bareNotifier) GetServiceDownNotificationTemplate() string   { return "" }
+
+func TestNewNotifierShouldReturnErrorIfTypeDoesNotEmbedNotifier(t *testing.T) {
+	if !HasType("bare") {
+		Register(NotifierType{Type: "bare", New: func(store *viper.Viper) model.Notify { return &bareNotifier{} }})
+	}
+
+	_, err := NewNotifier(model.NotifierInstance{Id: "1", Type: "bare"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "notifier type 'bare' does not embed model.Notifier", err.Error())
+}
@@ -0,0 +1,225 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNtfyServerUrl    = "https://ntfy.sh"
+	defaultNtfyUpPriority   = "low"
+	defaultNtfyDownPriority = "high"
+)
+
+type NtfyNotifier struct {
+	model.Notifier
+	ServerUrl    string
+	Topic        string
+	Token        string
+	UpPriority   string
+	DownPriority string
+	client       *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "ntfy",
+		New:  func(store *viper.Viper) model.Notify { return NewNtfyNotifier(store) },
+	})
+}
+
+func NewNtfyNotifier(store *viper.Viper) *NtfyNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_NTFY_ENABLED")
+	data["serverUrl"] = store.GetString("NOTIFIER_NTFY_SERVERURL")
+	data["topic"] = store.GetString("NOTIFIER_NTFY_TOPIC")
+	data["token"] = store.GetString("NOTIFIER_NTFY_TOKEN")
+	data["upPriority"] = store.GetString("NOTIFIER_NTFY_UPPRIORITY")
+	data["downPriority"] = store.GetString("NOTIFIER_NTFY_DOWNPRIORITY")
+
+	if value := data["serverUrl"].(string); len(value) == 0 {
+		data["serverUrl"] = defaultNtfyServerUrl
+	}
+
+	if value := data["upPriority"].(string); len(value) == 0 {
+		data["upPriority"] = defaultNtfyUpPriority
+	}
+
+	if value := data["downPriority"].(string); len(value) == 0 {
+		data["downPriority"] = defaultNtfyDownPriority
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_NTFY_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTextUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_NTFY_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTextDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "ntfy", defaultTextDigestTemplate)
+
+	return &NtfyNotifier{
+		Notifier: model.Notifier{
+			Id:      "ntfy",
+			Name:    "ntfy",
+			Enabled: store.GetBool("NOTIFIER_NTFY_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Server url",
+					FormControlName: "serverUrl",
+					Placeholder:     defaultNtfyServerUrl,
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Topic",
+					FormControlName: "topic",
+					Placeholder:     "monhttp-alerts",
+					Required:        true,
+				},
+				{
+					Type:            "password",
+					Title:           "Access token",
+					FormControlName: "token",
+					Placeholder:     "tk_AgQdq7mVBoFD37zQVN29RhuMzNIz2",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Up priority",
+					FormControlName: "upPriority",
+					Placeholder:     "min, low, default, high, urgent or 1 to 5",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Down priority",
+					FormControlName: "downPriority",
+					Placeholder:     "min, low, default, high, urgent or 1 to 5",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		ServerUrl:    strings.TrimSuffix(data["serverUrl"].(string), "/"),
+		Topic:        data["topic"].(string),
+		Token:        data["token"].(string),
+		UpPriority:   data["upPriority"].(string),
+		DownPriority: data["downPriority"].(string),
+		client:       &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *NtfyNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent publishes the message to the topic. Down notifications use the down priority, recoveries the up priority.
+func (n *NtfyNotifier) SendEvent(event model.NotificationEvent) error {
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", n.ServerUrl, n.Topic), strings.NewReader(event.Message))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Title", eventTitle(event))
+	request.Header.Set("Priority", n.DownPriority)
+	request.Header.Set("Tags", "rotating_light")
+	if event.IsUpNotification {
+		request.Header.Set("Priority", n.UpPriority)
+		request.Header.Set("Tags", "white_check_mark")
+	}
+
+	if len(event.Link) > 0 {
+		request.Header.Set("Click", event.Link)
+	}
+
+	if len(n.Token) > 0 {
+		request.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	return executeJsonRequest(n.client, request)
+}
+
+func (n *NtfyNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *NtfyNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *NtfyNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *NtfyNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *NtfyNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *NtfyNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *NtfyNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextUpTemplate
+}
+
+func (n *NtfyNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDownTemplate
+}
+
+func (n *NtfyNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDigestTemplate
+}
@@ -0,0 +1,231 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpsgenieApiUrl       = "https://api.opsgenie.com"
+	defaultOpsgeniePriority     = "P1"
+	defaultOpsgenieUpTemplate   = "{{if .IsDegraded}}Latency of service '{{.Name}}' is back to normal{{else}}Service '{{.Name}}' is up again{{end}}"
+	defaultOpsgenieDownTemplate = "Service '{{.Name}}' is {{if .IsDegraded}}degraded{{else}}down{{end}}"
+
+	opsgenieMaxMessageLength = 130
+	opsgenieSource           = "monhttp"
+)
+
+type OpsgenieNotifier struct {
+	model.Notifier
+	ApiKey   string
+	Priority string
+	ApiUrl   string
+	client   *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "opsgenie",
+		New:  func(store *viper.Viper) model.Notify { return NewOpsgenieNotifier(store) },
+	})
+}
+
+func NewOpsgenieNotifier(store *viper.Viper) *OpsgenieNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_OPSGENIE_ENABLED")
+	data["apiKey"] = store.GetString("NOTIFIER_OPSGENIE_APIKEY")
+	data["priority"] = store.GetString("NOTIFIER_OPSGENIE_PRIORITY")
+	data["apiUrl"] = store.GetString("NOTIFIER_OPSGENIE_APIURL")
+
+	if value := data["priority"].(string); len(value) == 0 {
+		data["priority"] = defaultOpsgeniePriority
+	}
+
+	if value := data["apiUrl"].(string); len(value) == 0 {
+		data["apiUrl"] = defaultOpsgenieApiUrl
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_OPSGENIE_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultOpsgenieUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_OPSGENIE_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultOpsgenieDownTemplate
+	}
+
+	return &OpsgenieNotifier{
+		Notifier: model.Notifier{
+			Id:      "opsgenie",
+			Name:    "Opsgenie",
+			Enabled: store.GetBool("NOTIFIER_OPSGENIE_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "password",
+					Title:           "Api key",
+					FormControlName: "apiKey",
+					Placeholder:     "Api key of an API integration",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Priority",
+					FormControlName: "priority",
+					Placeholder:     "P1 to P5",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Api url",
+					FormControlName: "apiUrl",
+					Placeholder:     "https://api.opsgenie.com or https://api.eu.opsgenie.com",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Note added when closing the alert of {{.Name}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Alert message for {{.Name}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		ApiKey:   data["apiKey"].(string),
+		Priority: data["priority"].(string),
+		ApiUrl:   strings.TrimSuffix(data["apiUrl"].(string), "/"),
+		client:   &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// SendNotification creates an alert with the message. Up and down notifications are handled by SendEvent.
+func (n *OpsgenieNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent creates an alert on a down notification and closes it on the up notification. The alert alias is
+// derived from the service id, so Opsgenie deduplicates repeated down notifications of a service.
+func (n *OpsgenieNotifier) SendEvent(event model.NotificationEvent) error {
+	alias := incidentKey(event.Service.Id)
+
+	if event.IsUpNotification {
+		body, err := toJson(opsgenieClose{Source: opsgenieSource, Note: event.Message})
+		if err != nil {
+			return err
+		}
+
+		closeUrl := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", n.ApiUrl, url.PathEscape(alias))
+		return n.send(closeUrl, body)
+	}
+
+	details := map[string]string{
+		"endpoint": event.Service.Endpoint,
+	}
+	if len(event.Link) > 0 {
+		details["link"] = event.Link
+	}
+
+	body, err := toJson(opsgenieAlert{
+		Message:     truncate(event.Message, opsgenieMaxMessageLength),
+		Alias:       alias,
+		Description: event.Failure.Reason,
+		Priority:    n.Priority,
+		Source:      opsgenieSource,
+		Entity:      event.Service.Name,
+		Tags:        event.Service.Tags,
+		Details:     details,
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.send(fmt.Sprintf("%s/v2/alerts", n.ApiUrl), body)
+}
+
+func (n *OpsgenieNotifier) send(requestUrl, body string) error {
+	request, err := http.NewRequest(http.MethodPost, requestUrl, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "GenieKey "+n.ApiKey)
+
+	return executeJsonRequest(n.client, request)
+}
+
+func (n *OpsgenieNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *OpsgenieNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *OpsgenieNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *OpsgenieNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *OpsgenieNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *OpsgenieNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *OpsgenieNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultOpsgenieUpTemplate
+}
+
+func (n *OpsgenieNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultOpsgenieDownTemplate
+}
@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPagerDutyApiUrl       = "https://events.pagerduty.com"
+	defaultPagerDutySeverity     = "critical"
+	defaultPagerDutyUpTemplate   = "{{if .IsDegraded}}Latency of service '{{.Name}}' is back to normal{{else}}Service '{{.Name}}' is up again{{end}}"
+	defaultPagerDutyDownTemplate = "Service '{{.Name}}' is {{if .IsDegraded}}degraded{{else}}down{{end}}: {{.Reason}}"
+
+	pagerDutyMaxSummaryLength = 1024
+)
+
+type PagerDutyNotifier struct {
+	model.Notifier
+	RoutingKey string
+	Severity   string
+	ApiUrl     string
+	client     *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "pagerduty",
+		New:  func(store *viper.Viper) model.Notify { return NewPagerDutyNotifier(store) },
+	})
+}
+
+func NewPagerDutyNotifier(store *viper.Viper) *PagerDutyNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_PAGERDUTY_ENABLED")
+	data["routingKey"] = store.GetString("NOTIFIER_PAGERDUTY_ROUTINGKEY")
+	data["severity"] = store.GetString("NOTIFIER_PAGERDUTY_SEVERITY")
+	data["apiUrl"] = store.GetString("NOTIFIER_PAGERDUTY_APIURL")
+
+	if value := data["severity"].(string); len(value) == 0 {
+		data["severity"] = defaultPagerDutySeverity
+	}
+
+	if value := data["apiUrl"].(string); len(value) == 0 {
+		data["apiUrl"] = defaultPagerDutyApiUrl
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_PAGERDUTY_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultPagerDutyUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_PAGERDUTY_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultPagerDutyDownTemplate
+	}
+
+	return &PagerDutyNotifier{
+		Notifier: model.Notifier{
+			Id:      "pagerduty",
+			Name:    "PagerDuty",
+			Enabled: store.GetBool("NOTIFIER_PAGERDUTY_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "password",
+					Title:           "Integration key",
+					FormControlName: "routingKey",
+					Placeholder:     "Events API v2 integration key",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Severity",
+					FormControlName: "severity",
+					Placeholder:     "critical, error, warning or info",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Api url",
+					FormControlName: "apiUrl",
+					Placeholder:     defaultPagerDutyApiUrl,
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up again",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Incident summary with {{.Name}} and {{.Reason}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		RoutingKey: data["routingKey"].(string),
+		Severity:   data["severity"].(string),
+		ApiUrl:     strings.TrimSuffix(data["apiUrl"].(string), "/"),
+		client:     &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component"`
+	CustomDetails map[string]string `json:"custom_details"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// SendNotification triggers an incident with the message. Up and down notifications are handled by SendEvent.
+func (n *PagerDutyNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent triggers an incident on a down notification and resolves it on the up notification. Both use the same
+// dedup key, so PagerDuty groups repeated down notifications of a service into one incident.
+func (n *PagerDutyNotifier) SendEvent(event model.NotificationEvent) error {
+	body := pagerDutyEvent{
+		RoutingKey:  n.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    incidentKey(event.Service.Id),
+	}
+
+	if !event.IsUpNotification {
+		body.EventAction = "trigger"
+		body.Payload = &pagerDutyPayload{
+			Summary:   truncate(event.Message, pagerDutyMaxSummaryLength),
+			Source:    serviceSource(event.Service),
+			Severity:  n.Severity,
+			Timestamp: event.Date.Format(time.RFC3339),
+			Component: event.Service.Name,
+			CustomDetails: map[string]string{
+				"reason":   event.Failure.Reason,
+				"endpoint": event.Service.Endpoint,
+			},
+		}
+
+		if len(event.Link) > 0 {
+			body.Links = []pagerDutyLink{{Href: event.Link, Text: "Open in monhttp"}}
+		}
+	}
+
+	message, err := toJson(body)
+	if err != nil {
+		return err
+	}
+
+	return postJson(n.client, fmt.Sprintf("%s/v2/enqueue", n.ApiUrl), message)
+}
+
+func (n *PagerDutyNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *PagerDutyNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *PagerDutyNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *PagerDutyNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *PagerDutyNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *PagerDutyNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *PagerDutyNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultPagerDutyUpTemplate
+}
+
+func (n *PagerDutyNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultPagerDutyDownTemplate
+}
@@ -0,0 +1,172 @@
+package notifier
+
+import (
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type pushRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+func newPushStub(requests *[]pushRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		*requests = append(*requests, pushRequest{
+			Method: request.Method,
+			Path:   request.URL.Path,
+			Header: request.Header,
+			Body:   string(body),
+		})
+	}))
+}
+
+func sendUpAndDownEvents(t *testing.T, notify model.Notify) {
+	for _, isUp := range []bool{false, true} {
+		event := newIncidentEvent(notify, isUp)
+		assert.Nil(t, SendEvent(notify, event), notify.GetId())
+	}
+}
+
+func TestNtfyNotifierShouldMapPriorities(t *testing.T) {
+	var requests []pushRequest
+	server := newPushStub(&requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_NTFY_SERVERURL", server.URL)
+	store.Set("NOTIFIER_NTFY_TOPIC", "alerts")
+	store.Set("NOTIFIER_NTFY_TOKEN", "tk_secret")
+	sendUpAndDownEvents(t, NewNtfyNotifier(store))
+
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "/alerts", requests[0].Path)
+	assert.Equal(t, "high", requests[0].Header.Get("Priority"))
+	assert.Equal(t, "Api is down", requests[0].Header.Get("Title"))
+	assert.Equal(t, "Bearer tk_secret", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "https://monhttp.example.com/services/2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10", requests[0].Header.Get("Click"))
+	assert.Equal(t, "Service 'Api' is down. Reason: 'timeout' at ", requests[0].Body)
+	assert.Equal(t, "low", requests[1].Header.Get("Priority"))
+	assert.Equal(t, "Service 'Api' is up again!", requests[1].Body)
+}
+
+func TestGotifyNotifierShouldMapPriorities(t *testing.T) {
+	var requests []pushRequest
+	server := newPushStub(&requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_GOTIFY_SERVERURL", server.URL)
+	store.Set("NOTIFIER_GOTIFY_APPTOKEN", "app-token")
+	store.Set("NOTIFIER_GOTIFY_UPPRIORITY", "1")
+	sendUpAndDownEvents(t, NewGotifyNotifier(store))
+
+	assert.Equal(t, 2, len(requests))
+
+	var down, up map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(requests[0].Body), &down))
+	assert.Nil(t, json.Unmarshal([]byte(requests[1].Body), &up))
+
+	assert.Equal(t, "/message", requests[0].Path)
+	assert.Equal(t, "app-token", requests[0].Header.Get("X-Gotify-Key"))
+	assert.Equal(t, float64(8), down["priority"])
+	assert.Equal(t, "Api is down", down["title"])
+	assert.Equal(t, float64(1), up["priority"])
+}
+
+func TestGotifyNotifierShouldReturnErrorForInvalidPriority(t *testing.T) {
+	store := viper.New()
+	store.Set("NOTIFIER_GOTIFY_DOWNPRIORITY", "urgent")
+	gotify := NewGotifyNotifier(store)
+
+	err := SendEvent(gotify, newIncidentEvent(gotify, false))
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid gotify priority 'urgent'", err.Error())
+}
+
+func TestPushoverNotifierShouldMapPriorities(t *testing.T) {
+	var requests []pushRequest
+	server := newPushStub(&requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_PUSHOVER_APIURL", server.URL)
+	store.Set("NOTIFIER_PUSHOVER_APPTOKEN", "app-token")
+	store.Set("NOTIFIER_PUSHOVER_USERKEY", "user-key")
+	store.Set("NOTIFIER_PUSHOVER_DOWNPRIORITY", "2")
+	sendUpAndDownEvents(t, NewPushoverNotifier(store))
+
+	assert.Equal(t, 2, len(requests))
+
+	down, _ := url.ParseQuery(requests[0].Body)
+	up, _ := url.ParseQuery(requests[1].Body)
+
+	assert.Equal(t, "/1/messages.json", requests[0].Path)
+	assert.Equal(t, "app-token", down.Get("token"))
+	assert.Equal(t, "user-key", down.Get("user"))
+	assert.Equal(t, "2", down.Get("priority"))
+	assert.Equal(t, "60", down.Get("retry"))
+	assert.Equal(t, "3600", down.Get("expire"))
+	assert.Equal(t, "-1", up.Get("priority"))
+	assert.Empty(t, up.Get("retry"))
+}
+
+func TestMatrixNotifierShouldSendHtmlMessageWithMessageType(t *testing.T) {
+	var requests []pushRequest
+	server := newPushStub(&requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_MATRIX_HOMESERVERURL", server.URL)
+	store.Set("NOTIFIER_MATRIX_ACCESSTOKEN", "access-token")
+	store.Set("NOTIFIER_MATRIX_ROOMID", "!room:example.com")
+	sendUpAndDownEvents(t, NewMatrixNotifier(store))
+
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, http.MethodPut, requests[0].Method)
+	assert.True(t, strings.HasPrefix(requests[0].Path, "/_matrix/client/v3/rooms/!room:example.com/send/m.room.message/"))
+	assert.Equal(t, "Bearer access-token", requests[0].Header.Get("Authorization"))
+	assert.NotEqual(t, requests[0].Path, requests[1].Path)
+
+	var down, up map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(requests[0].Body), &down))
+	assert.Nil(t, json.Unmarshal([]byte(requests[1].Body), &up))
+
+	assert.Equal(t, "m.text", down["msgtype"])
+	assert.Equal(t, "org.matrix.custom.html", down["format"])
+	assert.Contains(t, down["formatted_body"], "<b>'Api'</b>")
+	assert.Contains(t, down["body"], "Service 'Api' is down")
+	assert.Equal(t, "m.notice", up["msgtype"])
+}
+
+func TestMatrixNotifierShouldUseNotificationIdAsTransactionId(t *testing.T) {
+	var requests []pushRequest
+	server := newPushStub(&requests)
+	defer server.Close()
+
+	store := viper.New()
+	store.Set("NOTIFIER_MATRIX_HOMESERVERURL", server.URL)
+	store.Set("NOTIFIER_MATRIX_ROOMID", "!room:example.com")
+	notify := NewMatrixNotifier(store)
+
+	event := newIncidentEvent(notify, false)
+	event.NotificationId = "6f1c2a8e-3b7d-4e0f-9a55-1c2d3e4f5a6b"
+	for attempt := 0; attempt < 2; attempt++ {
+		assert.Nil(t, SendEvent(notify, event))
+	}
+
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "/_matrix/client/v3/rooms/!room:example.com/send/m.room.message/6f1c2a8e-3b7d-4e0f-9a55-1c2d3e4f5a6b", requests[0].Path)
+	assert.Equal(t, requests[0].Path, requests[1].Path)
+}
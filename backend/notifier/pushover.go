@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPushoverApiUrl       = "https://api.pushover.net"
+	defaultPushoverUpPriority   = "-1"
+	defaultPushoverDownPriority = "1"
+
+	// emergency notifications are repeated until they are acknowledged
+	pushoverEmergencyPriority = 2
+	pushoverEmergencyRetry    = "60"
+	pushoverEmergencyExpire   = "3600"
+)
+
+type PushoverNotifier struct {
+	model.Notifier
+	AppToken     string
+	UserKey      string
+	UpPriority   string
+	DownPriority string
+	ApiUrl       string
+	client       *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "pushover",
+		New:  func(store *viper.Viper) model.Notify { return NewPushoverNotifier(store) },
+	})
+}
+
+func NewPushoverNotifier(store *viper.Viper) *PushoverNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_PUSHOVER_ENABLED")
+	data["appToken"] = store.GetString("NOTIFIER_PUSHOVER_APPTOKEN")
+	data["userKey"] = store.GetString("NOTIFIER_PUSHOVER_USERKEY")
+	data["upPriority"] = store.GetString("NOTIFIER_PUSHOVER_UPPRIORITY")
+	data["downPriority"] = store.GetString("NOTIFIER_PUSHOVER_DOWNPRIORITY")
+	data["apiUrl"] = store.GetString("NOTIFIER_PUSHOVER_APIURL")
+
+	if value := data["upPriority"].(string); len(value) == 0 {
+		data["upPriority"] = defaultPushoverUpPriority
+	}
+
+	if value := data["downPriority"].(string); len(value) == 0 {
+		data["downPriority"] = defaultPushoverDownPriority
+	}
+
+	if value := data["apiUrl"].(string); len(value) == 0 {
+		data["apiUrl"] = defaultPushoverApiUrl
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_PUSHOVER_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTextUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_PUSHOVER_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTextDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "pushover", defaultTextDigestTemplate)
+
+	return &PushoverNotifier{
+		Notifier: model.Notifier{
+			Id:      "pushover",
+			Name:    "Pushover",
+			Enabled: store.GetBool("NOTIFIER_PUSHOVER_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "password",
+					Title:           "Application token",
+					FormControlName: "appToken",
+					Placeholder:     "azGDORePK8gMaC0QOYAMyEEuzJnyUi",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "User or group key",
+					FormControlName: "userKey",
+					Placeholder:     "uQiRzpo4DXghDmr9QzzfQu27cmVRsG",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Up priority",
+					FormControlName: "upPriority",
+					Placeholder:     "-2 to 2",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Down priority",
+					FormControlName: "downPriority",
+					Placeholder:     "-2 to 2, 2 repeats the notification until it is acknowledged",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Api url",
+					FormControlName: "apiUrl",
+					Placeholder:     defaultPushoverApiUrl,
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		AppToken:     data["appToken"].(string),
+		UserKey:      data["userKey"].(string),
+		UpPriority:   data["upPriority"].(string),
+		DownPriority: data["downPriority"].(string),
+		ApiUrl:       strings.TrimSuffix(data["apiUrl"].(string), "/"),
+		client:       &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *PushoverNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent sends the message to the user or group. Down notifications use the down priority, recoveries the up
+// priority.
+func (n *PushoverNotifier) SendEvent(event model.NotificationEvent) error {
+	title := eventTitle(event)
+	priorityValue := n.DownPriority
+	if event.IsUpNotification {
+		priorityValue = n.UpPriority
+	}
+
+	priority, err := strconv.Atoi(priorityValue)
+	if err != nil {
+		return fmt.Errorf("invalid pushover priority '%s'", priorityValue)
+	}
+
+	values := url.Values{}
+	values.Set("token", n.AppToken)
+	values.Set("user", n.UserKey)
+	values.Set("title", title)
+	values.Set("message", event.Message)
+	values.Set("priority", strconv.Itoa(priority))
+	values.Set("timestamp", strconv.FormatInt(event.Date.Unix(), 10))
+
+	if priority == pushoverEmergencyPriority {
+		values.Set("retry", pushoverEmergencyRetry)
+		values.Set("expire", pushoverEmergencyExpire)
+	}
+
+	if len(event.Link) > 0 {
+		values.Set("url", event.Link)
+		values.Set("url_title", "Open in monhttp")
+	}
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/1/messages.json", n.ApiUrl), strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return executeJsonRequest(n.client, request)
+}
+
+func (n *PushoverNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *PushoverNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *PushoverNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *PushoverNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *PushoverNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *PushoverNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *PushoverNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextUpTemplate
+}
+
+func (n *PushoverNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDownTemplate
+}
+
+func (n *PushoverNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDigestTemplate
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"sort"
+	"sync"
+)
+
+// NotifierType is a kind of notifier like email or slack. Every type registers itself in the init function of its
+// file, so adding a notifier does not require changes anywhere else.
+type NotifierType struct {
+	Type string
+	// New creates a notifier with the configuration NOTIFIER_<TYPE>_<KEY> of the store. The form schema and the
+	// default name are taken from a notifier created with an empty store
+	New func(store *viper.Viper) model.Notify
+	// Validate checks the values of a notifier that is created or updated beyond its forms. It gets the notifier with
+	// the defaults of the type applied and the form values of the request. Invalid values are returned as
+	// model.FieldErrorVo, the error rejects the notifier as a whole. It is optional
+	Validate func(notify model.Notify, data map[string]interface{}) ([]interface{}, error)
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]NotifierType)
+)
+
+// Register adds the notifier type to the registry. It panics if the type is registered twice.
+func Register(notifierType NotifierType) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, exists := registry[notifierType.Type]; exists {
+		panic(fmt.Sprintf("notifier type '%s' is registered twice", notifierType.Type))
+	}
+	registry[notifierType.Type] = notifierType
+}
+
+// Types returns the ids of all registered notifier types in alphabetical order.
+func Types() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	result := make([]string, 0, len(registry))
+	for notifierType := range registry {
+		result = append(result, notifierType)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getNotifierType(notifierType string) (NotifierType, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	registered, exists := registry[notifierType]
+	if !exists {
+		return NotifierType{}, fmt.Errorf("notifier type '%s' is unknown", notifierType)
+	}
+	return registered, nil
+}
+
+// HasType returns true if the notifier type is registered.
+func HasType(notifierType string) bool {
+	_, err := getNotifierType(notifierType)
+	return err == nil
+}
+
+// NewNotifierOfType creates a notifier of the type with the configuration NOTIFIER_<TYPE>_<KEY> of the store.
+func NewNotifierOfType(notifierType string, store *viper.Viper) (model.Notify, error) {
+	registered, err := getNotifierType(notifierType)
+	if err != nil {
+		return nil, err
+	}
+	return registered.New(store), nil
+}
+
+// TypeTemplates returns an unconfigured notifier of every type. Their name and forms describe the type.
+func TypeTemplates() []model.Notify {
+	result := make([]model.Notify, 0)
+	for _, notifierType := range Types() {
+		registered, err := getNotifierType(notifierType)
+		if err != nil {
+			continue
+		}
+		result = append(result, registered.New(viper.New()))
+	}
+	return result
+}
+
+// Validate checks the form values against the forms of the type with ValidateForm and runs the validation function of
+// the type. Fields that are already invalid according to their form are not checked again.
+func Validate(notify model.Notify, data map[string]interface{}) ([]interface{}, error) {
+	registered, err := getNotifierType(notify.GetType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := ValidateForm(notify, data)
+	if registered.Validate == nil {
+		return result, nil
+	}
+
+	validationErrors, err := registered.Validate(notify, data)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidFields := make(map[string]bool)
+	for _, validationError := range result {
+		invalidFields[validationError.(model.FieldErrorVo).Field] = true
+	}
+	for _, validationError := range validationErrors {
+		if fieldError, ok := validationError.(model.FieldErrorVo); ok && invalidFields[fieldError.Field] {
+			continue
+		}
+		result = append(result, validationError)
+	}
+	return result, nil
+}
@@ -0,0 +1,407 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	SyslogNetworkUdp  = "udp"
+	SyslogNetworkTcp  = "tcp"
+	SyslogNetworkUnix = "unix"
+
+	defaultSyslogUdpAddress       = "localhost:514"
+	defaultSyslogUnixAddress      = "/dev/log"
+	defaultSyslogFacility         = "daemon"
+	defaultSyslogUpSeverity       = "notice"
+	defaultSyslogDownSeverity     = "err"
+	defaultSyslogDegradedSeverity = "warning"
+	defaultSyslogAppName          = "monhttp"
+
+	// syslogStructuredDataId uses the example enterprise number of RFC 5612
+	syslogStructuredDataId = "monhttp@32473"
+	syslogTimeout          = 10 * time.Second
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7, "uucp": 8,
+	"cron": 9, "authpriv": 10, "ftp": 11, "local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20,
+	"local5": 21, "local6": 22, "local7": 23,
+}
+
+var syslogSeverities = map[string]int{
+	"emerg": 0, "alert": 1, "crit": 2, "err": 3, "warning": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+type SyslogNotifier struct {
+	model.Notifier
+	Network          string
+	Address          string
+	Facility         string
+	UpSeverity       string
+	DownSeverity     string
+	DegradedSeverity string
+	AppName          string
+	hostname         string
+}
+
+func init() {
+	Register(NotifierType{
+		Type:     "syslog",
+		New:      func(store *viper.Viper) model.Notify { return NewSyslogNotifier(store) },
+		Validate: validateSyslogData,
+	})
+}
+
+// validateSyslogData checks the network, the facility and the severities, which would otherwise only fail when a
+// notification is sent.
+func validateSyslogData(notify model.Notify, _ map[string]interface{}) ([]interface{}, error) {
+	n := notify.(*SyslogNotifier)
+	result := make([]interface{}, 0)
+
+	switch n.Network {
+	case SyslogNetworkUdp, SyslogNetworkTcp, SyslogNetworkUnix:
+	default:
+		result = append(result, model.FieldErrorVo{Field: "network", Message: fmt.Sprintf("'%s' is not one of udp, tcp or unix", n.Network)})
+	}
+
+	if _, exists := syslogFacilities[n.Facility]; !exists {
+		result = append(result, model.FieldErrorVo{Field: "facility", Message: fmt.Sprintf("'%s' is not a syslog facility", n.Facility)})
+	}
+
+	severities := []struct{ field, value string }{
+		{"upSeverity", n.UpSeverity}, {"downSeverity", n.DownSeverity}, {"degradedSeverity", n.DegradedSeverity},
+	}
+	for _, severity := range severities {
+		if _, exists := syslogSeverities[severity.value]; !exists {
+			result = append(result, model.FieldErrorVo{Field: severity.field, Message: fmt.Sprintf("'%s' is not a syslog severity", severity.value)})
+		}
+	}
+	return result, nil
+}
+
+func NewSyslogNotifier(store *viper.Viper) *SyslogNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_SYSLOG_ENABLED")
+	data["network"] = strings.ToLower(store.GetString("NOTIFIER_SYSLOG_NETWORK"))
+	data["address"] = store.GetString("NOTIFIER_SYSLOG_ADDRESS")
+	data["facility"] = strings.ToLower(store.GetString("NOTIFIER_SYSLOG_FACILITY"))
+	data["upSeverity"] = strings.ToLower(store.GetString("NOTIFIER_SYSLOG_UPSEVERITY"))
+	data["downSeverity"] = strings.ToLower(store.GetString("NOTIFIER_SYSLOG_DOWNSEVERITY"))
+	data["degradedSeverity"] = strings.ToLower(store.GetString("NOTIFIER_SYSLOG_DEGRADEDSEVERITY"))
+	data["appName"] = store.GetString("NOTIFIER_SYSLOG_APPNAME")
+
+	if value := data["network"].(string); len(value) == 0 {
+		data["network"] = SyslogNetworkUdp
+	}
+
+	if value := data["address"].(string); len(value) == 0 {
+		data["address"] = defaultSyslogUdpAddress
+		if data["network"] == SyslogNetworkUnix {
+			data["address"] = defaultSyslogUnixAddress
+		}
+	}
+
+	if value := data["facility"].(string); len(value) == 0 {
+		data["facility"] = defaultSyslogFacility
+	}
+
+	if value := data["upSeverity"].(string); len(value) == 0 {
+		data["upSeverity"] = defaultSyslogUpSeverity
+	}
+
+	if value := data["downSeverity"].(string); len(value) == 0 {
+		data["downSeverity"] = defaultSyslogDownSeverity
+	}
+
+	if value := data["degradedSeverity"].(string); len(value) == 0 {
+		data["degradedSeverity"] = defaultSyslogDegradedSeverity
+	}
+
+	if value := data["appName"].(string); len(value) == 0 {
+		data["appName"] = defaultSyslogAppName
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_SYSLOG_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTextUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_SYSLOG_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTextDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "syslog", defaultTextDigestTemplate)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	return &SyslogNotifier{
+		Notifier: model.Notifier{
+			Id:      "syslog",
+			Name:    "Syslog",
+			Enabled: store.GetBool("NOTIFIER_SYSLOG_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Network",
+					FormControlName: "network",
+					Placeholder:     "udp, tcp or unix",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Address",
+					FormControlName: "address",
+					Placeholder:     "localhost:514 or /dev/log",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Facility",
+					FormControlName: "facility",
+					Placeholder:     "daemon, local0 to local7, ...",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Up severity",
+					FormControlName: "upSeverity",
+					Placeholder:     "notice",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Down severity",
+					FormControlName: "downSeverity",
+					Placeholder:     "err",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Degraded severity",
+					FormControlName: "degradedSeverity",
+					Placeholder:     "warning",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "App name",
+					FormControlName: "appName",
+					Placeholder:     "monhttp",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is up",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Service {{.Name}} is down",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		Network:          data["network"].(string),
+		Address:          data["address"].(string),
+		Facility:         data["facility"].(string),
+		UpSeverity:       data["upSeverity"].(string),
+		DownSeverity:     data["downSeverity"].(string),
+		DegradedSeverity: data["degradedSeverity"].(string),
+		AppName:          data["appName"].(string),
+		hostname:         hostname,
+	}
+}
+
+func (n *SyslogNotifier) SendNotification(service model.Service, message string) error {
+	return n.SendEvent(model.NotificationEvent{Service: service, Message: message, Date: time.Now()})
+}
+
+// SendEvent writes the event as RFC 5424 message. Digests use the down severity.
+func (n *SyslogNotifier) SendEvent(event model.NotificationEvent) error {
+	message, err := n.buildMessage(event)
+	if err != nil {
+		return err
+	}
+
+	switch n.Network {
+	case SyslogNetworkUdp:
+		return n.write("udp", message, false)
+	case SyslogNetworkTcp:
+		return n.write("tcp", message, true)
+	case SyslogNetworkUnix:
+		// like log/syslog, prefer a datagram socket as used by /dev/log and fall back to a stream socket
+		if err := n.write("unixgram", message, false); err == nil {
+			return nil
+		}
+		return n.write("unix", message, true)
+	default:
+		return fmt.Errorf("invalid syslog network '%s'", n.Network)
+	}
+}
+
+// write sends the message over a new connection. Messages over stream sockets are framed with their length as
+// described in RFC 6587.
+func (n *SyslogNotifier) write(network string, message string, octetCounting bool) error {
+	conn, err := net.DialTimeout(network, n.Address, syslogTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+
+	if octetCounting {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+	_, err = conn.Write([]byte(message))
+	return err
+}
+
+// buildMessage returns the RFC 5424 message of the event. The state and the service are added as structured data,
+// e.g. [monhttp@32473 state="down" service="Api"].
+func (n *SyslogNotifier) buildMessage(event model.NotificationEvent) (string, error) {
+	facility, exists := syslogFacilities[n.Facility]
+	if !exists {
+		return "", fmt.Errorf("invalid syslog facility '%s'", n.Facility)
+	}
+
+	state, severityName := "down", n.DownSeverity
+	switch {
+	case event.IsDigest:
+		state = "digest"
+	case event.IsUpNotification && event.IsDegraded:
+		state, severityName = "normal", n.UpSeverity
+	case event.IsUpNotification:
+		state, severityName = "up", n.UpSeverity
+	case event.IsDegraded:
+		state, severityName = "degraded", n.DegradedSeverity
+	}
+
+	severity, exists := syslogSeverities[severityName]
+	if !exists {
+		return "", fmt.Errorf("invalid syslog severity '%s'", severityName)
+	}
+
+	date := event.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	structuredData := fmt.Sprintf(`[%s state="%s"`, syslogStructuredDataId, state)
+	if !event.IsDigest {
+		params := [][2]string{
+			{"service", event.Service.Name},
+			{"serviceId", event.Service.Id},
+			{"endpoint", event.Service.Endpoint},
+			{"type", string(event.Service.Type)},
+			{"reason", event.Failure.Reason},
+			{"link", event.Link},
+		}
+		for _, param := range params {
+			if len(param[1]) > 0 {
+				structuredData += fmt.Sprintf(` %s="%s"`, param[0], escapeSyslogParamValue(param[1]))
+			}
+		}
+	}
+	structuredData += "]"
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s", facility*8+severity, date.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(n.hostname, 255), syslogHeaderField(n.AppName, 48), os.Getpid(), strings.ToUpper(state),
+		structuredData, strings.Join(strings.Fields(event.Message), " ")), nil
+}
+
+// syslogHeaderField returns the value without spaces and limited to the maximum length of the header field, or the
+// nil value "-" if it is empty.
+func syslogHeaderField(value string, maxLength int) string {
+	value = strings.Join(strings.Fields(value), "")
+	if len(value) == 0 {
+		return "-"
+	}
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
+
+func escapeSyslogParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+func (n *SyslogNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *SyslogNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *SyslogNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *SyslogNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *SyslogNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *SyslogNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *SyslogNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextUpTemplate
+}
+
+func (n *SyslogNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDownTemplate
+}
+
+func (n *SyslogNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTextDigestTemplate
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newSyslogNotifier(network, address string) *SyslogNotifier {
+	store := viper.New()
+	store.Set("NOTIFIER_SYSLOG_NETWORK", network)
+	store.Set("NOTIFIER_SYSLOG_ADDRESS", address)
+	store.Set("NOTIFIER_SYSLOG_FACILITY", "local3")
+	return NewSyslogNotifier(store)
+}
+
+func TestSyslogNotifierShouldSendRfc5424MessageViaUdp(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	notify := newSyslogNotifier("udp", conn.LocalAddr().String())
+	assert.Nil(t, SendEvent(notify, newIncidentEvent(notify, false)))
+
+	buffer := make([]byte, 2048)
+	length, _, err := conn.ReadFrom(buffer)
+	assert.Nil(t, err)
+
+	message := string(buffer[:length])
+	// local3 * 8 + err
+	expectedHeader := fmt.Sprintf("<155>1 2020-12-20T10:00:00.000000Z %s monhttp %d DOWN ", notify.hostname, os.Getpid())
+	assert.True(t, strings.HasPrefix(message, expectedHeader), message)
+	assert.Contains(t, message, `[monhttp@32473 state="down" service="Api" serviceId="2d2f3c52-1ad1-4f0b-a4d4-2d9e5f4a7b10" endpoint="https://api.example.com/health" reason="timeout"`)
+	assert.True(t, strings.HasSuffix(message, "] Service 'Api' is down. Reason: 'timeout' at"), message)
+}
+
+func TestSyslogNotifierShouldFrameMessagesViaTcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		length, _ := reader.ReadString(' ')
+		size, _ := strconv.Atoi(strings.TrimSpace(length))
+		message := make([]byte, size)
+		_, _ = reader.Read(message)
+		received <- string(message)
+	}()
+
+	notify := newSyslogNotifier("tcp", listener.Addr().String())
+	assert.Nil(t, SendEvent(notify, newIncidentEvent(notify, true)))
+
+	message := <-received
+	// local3 * 8 + notice
+	assert.True(t, strings.HasPrefix(message, "<157>1 "), message)
+	assert.Contains(t, message, ` UP [monhttp@32473 state="up" `)
+}
+
+func TestSyslogNotifierShouldUseDegradedSeverityViaUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monhttp-syslog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	address := filepath.Join(dir, "log")
+	conn, err := net.ListenPacket("unixgram", address)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	notify := newSyslogNotifier("unix", address)
+	event := newIncidentEvent(notify, false)
+	event.IsDegraded = true
+	event.Failure.Reason = `p95 is "1200ms" ]`
+	assert.Nil(t, SendEvent(notify, event))
+
+	buffer := make([]byte, 2048)
+	length, _, err := conn.ReadFrom(buffer)
+	assert.Nil(t, err)
+
+	message := string(buffer[:length])
+	// local3 * 8 + warning
+	assert.True(t, strings.HasPrefix(message, "<156>1 "), message)
+	assert.Contains(t, message, ` DEGRADED [monhttp@32473 state="degraded" `)
+	assert.Contains(t, message, `reason="p95 is \"1200ms\" \]"`)
+}
+
+func TestSyslogNotifierShouldRejectUnknownFacility(t *testing.T) {
+	notify := newSyslogNotifier("udp", "127.0.0.1:514")
+	notify.Facility = "local9"
+
+	err := notify.SendEvent(model.NotificationEvent{Service: model.Service{Name: "Api"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid syslog facility 'local9'", err.Error())
+}
@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+const (
+	defaultTeamsUpTemplate = `{"type": "message", "attachments": [{"contentType": "application/vnd.microsoft.card.adaptive", "content": {
+	"$schema": "http://adaptivecards.io/schemas/adaptive-card.json", "type": "AdaptiveCard", "version": "1.4",
+	"body": [
+		{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "color": "Good", "text": {{if .IsDegraded}}{{json (printf "Latency of %s is back to normal" .Name)}}{{else}}{{json (printf "%s is up again" .Name)}}{{end}}},
+		{"type": "TextBlock", "isSubtle": true, "spacing": "None", "text": {{json .Date}}}
+	]{{if .Link}},
+	"actions": [{"type": "Action.OpenUrl", "title": "Open in monhttp", "url": {{json .Link}}}]{{end}}
+}}]}`
+	defaultTeamsDownTemplate = `{"type": "message", "attachments": [{"contentType": "application/vnd.microsoft.card.adaptive", "content": {
+	"$schema": "http://adaptivecards.io/schemas/adaptive-card.json", "type": "AdaptiveCard", "version": "1.4",
+	"body": [
+		{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "color": "Attention", "text": {{if .IsDegraded}}{{json (printf "%s is degraded" .Name)}}{{else}}{{json (printf "%s is down" .Name)}}{{end}}},
+		{"type": "TextBlock", "wrap": true, "text": {{json (printf "Reason: %s" .Reason)}}},
+		{"type": "TextBlock", "isSubtle": true, "spacing": "None", "text": {{json .Date}}}
+	]{{if .Link}},
+	"actions": [{"type": "Action.OpenUrl", "title": "Open in monhttp", "url": {{json .Link}}}]{{end}}
+}}]}`
+	defaultTeamsDigestTemplate = `{"type": "message", "attachments": [{"contentType": "application/vnd.microsoft.card.adaptive", "content": {
+	"$schema": "http://adaptivecards.io/schemas/adaptive-card.json", "type": "AdaptiveCard", "version": "1.4",
+	"body": [
+		{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "color": "Warning", "text": {{json (printf "%d services down, %d recovered" .DownCount .UpCount)}}},{{range .Down}}
+		{"type": "TextBlock", "wrap": true, "text": {{if .IsDegraded}}{{json (printf "%s is degraded. Reason: %s" .Name .Reason)}}{{else}}{{json (printf "%s is down. Reason: %s" .Name .Reason)}}{{end}}},{{end}}{{range .Up}}
+		{"type": "TextBlock", "wrap": true, "text": {{if .IsDegraded}}{{json (printf "Latency of %s is back to normal" .Name)}}{{else}}{{json (printf "%s is up again" .Name)}}{{end}}},{{end}}
+		{"type": "TextBlock", "isSubtle": true, "spacing": "None", "text": {{json .Date}}}
+	]
+}}]}`
+)
+
+type TeamsNotifier struct {
+	model.Notifier
+	WebhookUrl string
+	client     *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "teams",
+		New:  func(store *viper.Viper) model.Notify { return NewTeamsNotifier(store) },
+	})
+}
+
+func NewTeamsNotifier(store *viper.Viper) *TeamsNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_TEAMS_ENABLED")
+	data["webhookUrl"] = store.GetString("NOTIFIER_TEAMS_WEBHOOKURL")
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_TEAMS_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultTeamsUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_TEAMS_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultTeamsDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "teams", defaultTeamsDigestTemplate)
+
+	return &TeamsNotifier{
+		Notifier: model.Notifier{
+			Id:      "teams",
+			Name:    "Microsoft Teams",
+			Enabled: store.GetBool("NOTIFIER_TEAMS_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Incoming webhook url",
+					FormControlName: "webhookUrl",
+					Placeholder:     "https://example.webhook.office.com/webhookb2/...",
+					Required:        true,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     "Adaptive Card payload for {{.Name}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     "Adaptive Card payload for {{.Name}} and {{.Reason}}",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		WebhookUrl: store.GetString("NOTIFIER_TEAMS_WEBHOOKURL"),
+		client:     &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *TeamsNotifier) SendNotification(service model.Service, message string) error {
+	return postJson(n.client, n.WebhookUrl, message)
+}
+
+func (n *TeamsNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *TeamsNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *TeamsNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *TeamsNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *TeamsNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *TeamsNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *TeamsNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTeamsUpTemplate
+}
+
+func (n *TeamsNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTeamsDownTemplate
+}
+
+func (n *TeamsNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTeamsDigestTemplate
+}
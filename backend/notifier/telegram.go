@@ -3,17 +3,30 @@ package notifier
 import (
 	"fmt"
 	"github.com/koloo91/monhttp/model"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 )
 
+const (
+	// telegram does not support <br> in html messages
+	defaultTelegramDigestTemplate = "<b>{{.DownCount}} services down, {{.UpCount}} recovered</b>" +
+		"{{range .Down}}\nService <b>'{{.Name}}'</b> is {{if .IsDegraded}}degraded{{else}}down{{end}}. Reason: '{{.Reason}}'{{end}}" +
+		"{{range .Up}}\n{{if .IsDegraded}}Latency of service <b>'{{.Name}}'</b> is back to normal{{else}}Service <b>'{{.Name}}'</b> is up again!{{end}}{{end}}"
+)
+
 type TelegramNotifier struct {
 	model.Notifier
 	ApiToken string
 	Channel  string
+	client   *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "telegram",
+		New:  func(store *viper.Viper) model.Notify { return NewTelegramNotifier(store) },
+	})
 }
 
 func NewTelegramNotifier(store *viper.Viper) *TelegramNotifier {
@@ -32,11 +45,13 @@ func NewTelegramNotifier(store *viper.Viper) *TelegramNotifier {
 		data["SERVICE_DOWN_TEMPLATE"] = defaultDownTemplate
 	}
 
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "telegram", defaultTelegramDigestTemplate)
+
 	return &TelegramNotifier{
 		Notifier: model.Notifier{
 			Id:      "telegram",
 			Name:    "Telegram Notifier",
-			Enabled: viper.GetBool("NOTIFIER_TELEGRAM_ENABLED"),
+			Enabled: store.GetBool("NOTIFIER_TELEGRAM_ENABLED"),
 			Data:    data,
 			Form: []model.NotificationForm{
 				{
@@ -66,6 +81,7 @@ func NewTelegramNotifier(store *viper.Viper) *TelegramNotifier {
 					FormControlName: "SERVICE_UP_TEMPLATE",
 					Placeholder:     "Service {{.Name}} is up",
 					Required:        true,
+					Format:          model.FormFormatTemplate,
 				},
 				{
 					Type:            "textarea",
@@ -73,11 +89,21 @@ func NewTelegramNotifier(store *viper.Viper) *TelegramNotifier {
 					FormControlName: "SERVICE_DOWN_TEMPLATE",
 					Placeholder:     "Service {{.Name}} is down",
 					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
 				},
 			},
 		},
 		ApiToken: store.GetString("NOTIFIER_TELEGRAM_APITOKEN"),
 		Channel:  store.GetString("NOTIFIER_TELEGRAM_CHANNEL"),
+		client:   &http.Client{Timeout: jsonRequestTimeout},
 	}
 }
 
@@ -86,28 +112,18 @@ func (n *TelegramNotifier) SendNotification(service model.Service, message strin
 }
 
 func (n *TelegramNotifier) send(message string) error {
-
 	v := url.Values{}
 	v.Set("chat_id", n.Channel)
 	v.Set("text", message)
-	v.Encode()
+	v.Set("parse_mode", "HTML")
 
-	apiEndpoint := fmt.Sprintf("https://api.telegram.org/bot%v/sendMessage?%s&parse_mode=HTML", n.ApiToken, v.Encode())
-
-	response, err := http.Get(apiEndpoint)
-	if err != nil {
-		return err
-	}
-	defer response.Body.Close()
-
-	contentByte, err := ioutil.ReadAll(response.Body)
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://api.telegram.org/bot%v/sendMessage?%s", n.ApiToken, v.Encode()), nil)
 	if err != nil {
 		return err
 	}
 
-	log.Println(string(contentByte))
-
-	return nil
+	// the telegram api responds with 400 or 403 if the token or the channel is wrong
+	return executeJsonRequest(n.client, request)
 }
 
 func (n *TelegramNotifier) GetId() string {
@@ -141,5 +157,12 @@ func (n *TelegramNotifier) GetServiceDownNotificationTemplate() string {
 	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
 		return data.(string)
 	}
-	return "Service <b>'{{.Name}}'</b> is down. Reason: '{{.Reason}}' at {{.Date}}"
+	return defaultDownTemplate
+}
+
+func (n *TelegramNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultTelegramDigestTemplate
 }
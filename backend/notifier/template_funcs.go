@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// templateFuncs returns the functions that are available in all notification templates.
+func templateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"duration":   formatDuration,
+		"formatTime": formatTime,
+	}
+}
+
+// formatDuration formats the duration with its two largest units, e.g. "14m" or "1h 5m".
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	if duration < time.Second {
+		return "0s"
+	}
+
+	units := []struct {
+		suffix string
+		length time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	parts := make([]string, 0, 2)
+	for _, unit := range units {
+		value := duration / unit.length
+		duration -= value * unit.length
+
+		if value > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", value, unit.suffix))
+		} else if len(parts) > 0 {
+			break
+		}
+
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatTime formats a time or a RFC3339 string like .Date in the time zone with the layout of the time package,
+// e.g. {{formatTime "Europe/Berlin" "02.01.2006 15:04" .Date}}. Empty strings are returned unchanged.
+func formatTime(timeZone, layout string, value interface{}) (string, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return "", err
+	}
+
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		if len(v) == 0 {
+			return "", nil
+		}
+		if t, err = time.Parse(time.RFC3339, v); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("formatTime expects a time or a RFC3339 string, got %T", value)
+	}
+
+	return t.In(location).Format(layout), nil
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFormatDurationShouldUseTwoLargestUnits(t *testing.T) {
+	assert.Equal(t, "0s", formatDuration(0))
+	assert.Equal(t, "45s", formatDuration(45*time.Second))
+	assert.Equal(t, "14m", formatDuration(14*time.Minute))
+	assert.Equal(t, "3m 20s", formatDuration(3*time.Minute+20*time.Second))
+	assert.Equal(t, "1h 5m", formatDuration(time.Hour+5*time.Minute+30*time.Second))
+	assert.Equal(t, "1h", formatDuration(time.Hour+5*time.Second))
+	assert.Equal(t, "2d 3h", formatDuration(51*time.Hour))
+}
+
+func TestFormatTimeShouldConvertTimeZone(t *testing.T) {
+	value, err := formatTime("Europe/Berlin", "02.01.2006 15:04", "2020-12-20T10:00:00Z")
+	assert.Nil(t, err)
+	assert.Equal(t, "20.12.2020 11:00", value)
+
+	value, err = formatTime("America/New_York", time.Kitchen, time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, "8:00AM", value)
+
+	value, err = formatTime("UTC", time.RFC3339, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+
+	_, err = formatTime("Mars/Olympus_Mons", time.RFC3339, "2020-12-20T10:00:00Z")
+	assert.NotNil(t, err)
+}
+
+func TestNewTemplateDataShouldSetDowntimeOfUpNotifications(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 14, 0, 0, time.UTC)
+	downSince := date.Add(-14 * time.Minute)
+	service := model.Service{Id: "1", Name: "Api", Type: model.ServiceTypeHttp, Endpoint: "https://api.example.com"}
+	details := model.NotificationDetails{StatusCode: 200, LatencyInMs: 120, ConsecutiveFailures: 28, DownSince: &downSince}
+
+	up := NewTemplateData(service, true, details, date)
+	assert.Equal(t, "https://api.example.com", up.Endpoint)
+	assert.Equal(t, model.ServiceType(model.ServiceTypeHttp), up.Type)
+	assert.Equal(t, 200, up.StatusCode)
+	assert.Equal(t, int64(120), up.LatencyInMs)
+	assert.Equal(t, 28, up.ConsecutiveFailures)
+	assert.Equal(t, "2020-12-20T10:00:00Z", up.DownSince)
+	assert.Equal(t, 14*time.Minute, up.Downtime)
+
+	down := NewTemplateData(service, false, details, date)
+	assert.Equal(t, "2020-12-20T10:00:00Z", down.DownSince)
+	assert.Equal(t, time.Duration(0), down.Downtime)
+}
+
+func TestRenderNotificationShouldProvideTemplateFunctions(t *testing.T) {
+	date := time.Date(2020, 12, 20, 10, 14, 0, 0, time.UTC)
+	downSince := date.Add(-14 * time.Minute)
+	service := model.Service{Id: "1", Name: "Api"}
+	details := model.NotificationDetails{DownSince: &downSince}
+
+	email := NewEMailNotifier(viper.New())
+	message, err := RenderNotification(email, service, true, details, date)
+	assert.Nil(t, err)
+	assert.Equal(t, "Service <b>'Api'</b> is up again! It was down for 14m.", message)
+
+	store := viper.New()
+	store.Set("NOTIFIER_WEBHOOK_SERVICE_UP_TEMPLATE",
+		`{"downtime": {{json (duration .Downtime)}}, "since": {{json (formatTime "Europe/Berlin" "15:04" .DownSince)}}}`)
+	message, err = RenderNotification(NewWebhookNotifier(store), service, true, details, date)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"downtime": "14m", "since": "11:00"}`, message)
+}
@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"math"
+	"net/mail"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+// ValidateForm checks the form values of a notifier against the forms of its type and returns a model.FieldErrorVo
+// per invalid field. Unknown fields and values of the wrong type are rejected. The formats are checked on the values
+// with the defaults of the type applied. Required fields are only checked if the notifier is enabled, so that an
+// incomplete notifier can be stored disabled.
+func ValidateForm(notify model.Notify, data map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0)
+
+	forms := make(map[string]model.NotificationForm)
+	for _, form := range notify.GetForms() {
+		forms[form.FormControlName] = form
+	}
+
+	unknownFields := make([]string, 0)
+	for field := range data {
+		if _, exists := forms[field]; !exists {
+			unknownFields = append(unknownFields, field)
+		}
+	}
+	sort.Strings(unknownFields)
+	for _, field := range unknownFields {
+		result = append(result, model.FieldErrorVo{Field: field, Message: "is unknown"})
+	}
+
+	values := notify.GetData()
+	enabled, _ := values["enabled"].(bool)
+
+	for _, form := range notify.GetForms() {
+		if value, exists := data[form.FormControlName]; exists && value != nil && !hasFormType(form.Type, value) {
+			result = append(result, model.FieldErrorVo{Field: form.FormControlName, Message: "must be " + formTypeName(form.Type)})
+			continue
+		}
+
+		value := values[form.FormControlName]
+		if isEmptyFormValue(value) {
+			if form.Required && enabled {
+				result = append(result, model.FieldErrorVo{Field: form.FormControlName, Message: "is required"})
+			}
+			continue
+		}
+
+		if err := validateFormFormat(notify, form, value); err != nil {
+			result = append(result, model.FieldErrorVo{Field: form.FormControlName, Message: err.Error()})
+		}
+	}
+
+	return result
+}
+
+func hasFormType(formType string, value interface{}) bool {
+	switch formType {
+	case "switch":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		switch number := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return number == math.Trunc(number)
+		}
+		return false
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+func formTypeName(formType string) string {
+	switch formType {
+	case "switch":
+		return "a boolean"
+	case "number":
+		return "a whole number"
+	default:
+		return "a string"
+	}
+}
+
+func isEmptyFormValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(strings.TrimSpace(v)) == 0
+	case int:
+		return v == 0
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+func validateFormFormat(notify model.Notify, form model.NotificationForm, value interface{}) error {
+	switch form.Format {
+	case model.FormFormatPort:
+		port, ok := toFormInt(value)
+		if !ok || port < minPort || port > maxPort {
+			return fmt.Errorf("must be between %d and %d", minPort, maxPort)
+		}
+	case model.FormFormatEmail:
+		return validateEMailAddress(fmt.Sprint(value))
+	case model.FormFormatEmailList:
+		for _, address := range strings.Split(fmt.Sprint(value), ",") {
+			if address = strings.TrimSpace(address); len(address) == 0 {
+				continue
+			}
+			if err := validateEMailAddress(address); err != nil {
+				return err
+			}
+		}
+	case model.FormFormatTemplate:
+		if err := parseTemplate(notify, form.FormControlName, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("is not a valid template: %s", err)
+		}
+	}
+	return nil
+}
+
+func toFormInt(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case int:
+		return int64(number), true
+	case int64:
+		return number, true
+	case float64:
+		return int64(number), number == math.Trunc(number)
+	}
+	return 0, false
+}
+
+func validateEMailAddress(address string) error {
+	if _, err := mail.ParseAddress(address); err != nil {
+		return fmt.Errorf("'%s' is not a valid email address", address)
+	}
+	return nil
+}
+
+// parseTemplate parses the template with the functions that are available when the notifier renders it. The json
+// function is only available to notifiers with their own renderer.
+func parseTemplate(notify model.Notify, name, text string) error {
+	funcs := templateFuncs()
+	if _, ok := notify.(model.TemplateRenderer); ok {
+		funcs["json"] = toJson
+	}
+
+	_, err := template.New(name).Funcs(funcs).Parse(text)
+	return err
+}
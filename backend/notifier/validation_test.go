@@ -0,0 +1,142 @@
+package notifier
+
+import (
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func validateFormOfType(t *testing.T, notifierType string, data map[string]interface{}) []interface{} {
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: notifierType, Name: "Test", Data: data})
+	assert.Nil(t, err)
+	return ValidateForm(notify, data)
+}
+
+func validateOfType(t *testing.T, notifierType string, data map[string]interface{}) ([]interface{}, error) {
+	notify, err := NewNotifier(model.NotifierInstance{Id: "1", Type: notifierType, Name: "Test", Data: data})
+	assert.Nil(t, err)
+	return Validate(notify, data)
+}
+
+func TestValidateFormShouldAcceptValidEMailNotifier(t *testing.T) {
+	validationErrors := validateFormOfType(t, "email", map[string]interface{}{
+		"enabled": true,
+		"host":    "smtp.example.com",
+		"port":    float64(587),
+		"from":    "monhttp@example.com",
+		"to":      "ops@example.com, dev@example.com",
+		"subject": "[monhttp] {{.Name}}",
+	})
+	assert.Empty(t, validationErrors)
+}
+
+func TestValidateFormShouldReturnErrorPerField(t *testing.T) {
+	validationErrors := validateFormOfType(t, "email", map[string]interface{}{
+		"enabled":             true,
+		"host":                "smtp.example.com",
+		"port":                float64(70000),
+		"from":                "monhttp",
+		"to":                  "ops@example.com,dev",
+		"SERVICE_UP_TEMPLATE": "{{.Name",
+		"color":               "red",
+	})
+
+	assert.Equal(t, []interface{}{
+		model.FieldErrorVo{Field: "color", Message: "is unknown"},
+		model.FieldErrorVo{Field: "port", Message: "must be between 1 and 65535"},
+		model.FieldErrorVo{Field: "from", Message: "'monhttp' is not a valid email address"},
+		model.FieldErrorVo{Field: "to", Message: "'dev' is not a valid email address"},
+		model.FieldErrorVo{Field: "SERVICE_UP_TEMPLATE", Message: "is not a valid template: template: SERVICE_UP_TEMPLATE:1: unclosed action"},
+	}, validationErrors)
+}
+
+func TestValidateFormShouldRejectWrongTypes(t *testing.T) {
+	validationErrors := validateFormOfType(t, "exec", map[string]interface{}{
+		"enabled":          "yes",
+		"command":          float64(42),
+		"timeoutInSeconds": "30",
+	})
+
+	assert.Equal(t, []interface{}{
+		model.FieldErrorVo{Field: "enabled", Message: "must be a boolean"},
+		model.FieldErrorVo{Field: "command", Message: "must be a string"},
+		model.FieldErrorVo{Field: "timeoutInSeconds", Message: "must be a whole number"},
+	}, validationErrors)
+}
+
+func TestValidateFormShouldOnlyRequireFieldsOfEnabledNotifiers(t *testing.T) {
+	assert.Empty(t, validateFormOfType(t, "telegram", map[string]interface{}{"enabled": false}))
+
+	assert.Equal(t, []interface{}{
+		model.FieldErrorVo{Field: "apiToken", Message: "is required"},
+		model.FieldErrorVo{Field: "channel", Message: "is required"},
+	}, validateFormOfType(t, "telegram", map[string]interface{}{"enabled": true}))
+}
+
+func TestValidateFormShouldAllowJsonFunctionOnlyForJsonTemplates(t *testing.T) {
+	template := map[string]interface{}{"SERVICE_DOWN_TEMPLATE": `{"text": {{json .Name}}}`}
+
+	assert.Empty(t, validateFormOfType(t, "webhook", template))
+	assert.Len(t, validateFormOfType(t, "telegram", template), 1)
+}
+
+func TestValidateShouldRejectExecNotifiersUnlessEnabled(t *testing.T) {
+	_, err := validateOfType(t, "exec", map[string]interface{}{"command": "/bin/true"})
+	assert.Equal(t, ErrExecNotifierDisabled, err)
+
+	viper.Set("EXEC_NOTIFIER_ENABLED", true)
+	defer viper.Set("EXEC_NOTIFIER_ENABLED", false)
+
+	for _, timeout := range []float64{-1, 0, 601} {
+		validationErrors, err := validateOfType(t, "exec", map[string]interface{}{"command": "/bin/true", "timeoutInSeconds": timeout})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{
+			model.FieldErrorVo{Field: "timeoutInSeconds", Message: "must be between 1 and 600"},
+		}, validationErrors)
+	}
+
+	validationErrors, err := validateOfType(t, "exec", map[string]interface{}{"command": "/bin/true"})
+	assert.Nil(t, err)
+	assert.Empty(t, validationErrors)
+}
+
+func TestValidateShouldCheckSyslogValues(t *testing.T) {
+	validationErrors, err := validateOfType(t, "syslog", map[string]interface{}{"network": "tcp", "facility": "LOCAL0"})
+	assert.Nil(t, err)
+	assert.Empty(t, validationErrors)
+
+	validationErrors, err = validateOfType(t, "syslog", map[string]interface{}{
+		"network":      "sctp",
+		"facility":     "local9",
+		"downSeverity": "panic",
+		"upSeverity":   float64(5),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		model.FieldErrorVo{Field: "upSeverity", Message: "must be a string"},
+		model.FieldErrorVo{Field: "network", Message: "'sctp' is not one of udp, tcp or unix"},
+		model.FieldErrorVo{Field: "facility", Message: "'local9' is not a syslog facility"},
+		model.FieldErrorVo{Field: "downSeverity", Message: "'panic' is not a syslog severity"},
+	}, validationErrors)
+}
+
+func TestValidateShouldCheckEMailSecurityAndAuthMode(t *testing.T) {
+	validationErrors, err := validateOfType(t, "email", map[string]interface{}{"security": "starttls", "authMode": "CRAM-MD5"})
+	assert.Nil(t, err)
+	assert.Empty(t, validationErrors)
+
+	validationErrors, err = validateOfType(t, "email", map[string]interface{}{"security": "ssl", "authMode": "cram"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		model.FieldErrorVo{Field: "security", Message: "'ssl' is not one of auto, tls, starttls or none"},
+		model.FieldErrorVo{Field: "authMode", Message: "'cram' is not one of plain, login, cram-md5 or none"},
+	}, validationErrors)
+}
+
+func TestValidateShouldReturnErrorForUnknownType(t *testing.T) {
+	notify := NewWebhookNotifier(viper.New())
+	notify.Type = "carrier-pigeon"
+	_, err := Validate(notify, nil)
+	assert.Equal(t, "notifier type 'carrier-pigeon' is unknown", err.Error())
+}
@@ -0,0 +1,214 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultWebhookMethod         = http.MethodPost
+	defaultWebhookUpTemplate     = `{"event": "{{if .IsDegraded}}normal{{else}}up{{end}}", "service": {{json .Name}}, "date": {{json .Date}}}`
+	defaultWebhookDownTemplate   = `{"event": "{{if .IsDegraded}}degraded{{else}}down{{end}}", "service": {{json .Name}}, "reason": {{json .Reason}}, "date": {{json .Date}}}`
+	defaultWebhookDigestTemplate = `{"event": "digest", "downCount": {{.DownCount}}, "upCount": {{.UpCount}}, "down": [{{range $i, $e := .Down}}{{if $i}}, {{end}}{"service": {{json $e.Name}}, "degraded": {{$e.IsDegraded}}, "reason": {{json $e.Reason}}, "date": {{json $e.Date}}}{{end}}], "up": [{{range $i, $e := .Up}}{{if $i}}, {{end}}{"service": {{json $e.Name}}, "degraded": {{$e.IsDegraded}}, "date": {{json $e.Date}}}{{end}}], "date": {{json .Date}}}`
+
+	webhookSignatureHeader = "X-Monhttp-Signature"
+)
+
+type WebhookNotifier struct {
+	model.Notifier
+	Url     string
+	Method  string
+	Headers string
+	Secret  string
+	client  *http.Client
+}
+
+func init() {
+	Register(NotifierType{
+		Type: "webhook",
+		New:  func(store *viper.Viper) model.Notify { return NewWebhookNotifier(store) },
+	})
+}
+
+func NewWebhookNotifier(store *viper.Viper) *WebhookNotifier {
+	data := make(map[string]interface{})
+	data["enabled"] = store.GetBool("NOTIFIER_WEBHOOK_ENABLED")
+	data["url"] = store.GetString("NOTIFIER_WEBHOOK_URL")
+	data["method"] = store.GetString("NOTIFIER_WEBHOOK_METHOD")
+	data["headers"] = store.GetString("NOTIFIER_WEBHOOK_HEADERS")
+	data["secret"] = store.GetString("NOTIFIER_WEBHOOK_SECRET")
+
+	if value := data["method"].(string); len(value) == 0 {
+		data["method"] = defaultWebhookMethod
+	}
+
+	data["SERVICE_UP_TEMPLATE"] = store.GetString("NOTIFIER_WEBHOOK_SERVICE_UP_TEMPLATE")
+	if value, exists := data["SERVICE_UP_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_UP_TEMPLATE"] = defaultWebhookUpTemplate
+	}
+
+	data["SERVICE_DOWN_TEMPLATE"] = store.GetString("NOTIFIER_WEBHOOK_SERVICE_DOWN_TEMPLATE")
+	if value, exists := data["SERVICE_DOWN_TEMPLATE"]; !exists || len(value.(string)) == 0 {
+		data["SERVICE_DOWN_TEMPLATE"] = defaultWebhookDownTemplate
+	}
+
+	data["DIGEST_TEMPLATE"] = digestTemplate(store, "webhook", defaultWebhookDigestTemplate)
+
+	return &WebhookNotifier{
+		Notifier: model.Notifier{
+			Id:      "webhook",
+			Name:    "Webhook",
+			Enabled: store.GetBool("NOTIFIER_WEBHOOK_ENABLED"),
+			Data:    data,
+			Form: []model.NotificationForm{
+				{
+					Type:            "switch",
+					Title:           "Enabled",
+					FormControlName: "enabled",
+					Placeholder:     "Enabled",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Url",
+					FormControlName: "url",
+					Placeholder:     "https://example.com/hooks/monhttp",
+					Required:        true,
+				},
+				{
+					Type:            "text",
+					Title:           "Method",
+					FormControlName: "method",
+					Placeholder:     "POST",
+					Required:        false,
+				},
+				{
+					Type:            "text",
+					Title:           "Headers",
+					FormControlName: "headers",
+					Placeholder:     "Content-Type:application/json;Authorization:Bearer token",
+					Required:        false,
+				},
+				{
+					Type:            "password",
+					Title:           "HMAC secret",
+					FormControlName: "secret",
+					Placeholder:     "signs the body as X-Monhttp-Signature: sha256=<hex>",
+					Required:        false,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Up template",
+					FormControlName: "SERVICE_UP_TEMPLATE",
+					Placeholder:     defaultWebhookUpTemplate,
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Down template",
+					FormControlName: "SERVICE_DOWN_TEMPLATE",
+					Placeholder:     defaultWebhookDownTemplate,
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+				{
+					Type:            "textarea",
+					Title:           "Digest template",
+					FormControlName: "DIGEST_TEMPLATE",
+					Placeholder:     "{{.DownCount}} services down, {{.UpCount}} recovered",
+					Required:        true,
+					Format:          model.FormFormatTemplate,
+				},
+			},
+		},
+		Url:     data["url"].(string),
+		Method:  data["method"].(string),
+		Headers: data["headers"].(string),
+		Secret:  data["secret"].(string),
+		client:  &http.Client{Timeout: jsonRequestTimeout},
+	}
+}
+
+func (n *WebhookNotifier) SendNotification(service model.Service, message string) error {
+	return n.send(message)
+}
+
+func (n *WebhookNotifier) send(body string) error {
+	request, err := http.NewRequest(n.Method, n.Url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for _, header := range strings.Split(n.Headers, ";") {
+		headerValues := strings.SplitN(header, ":", 2)
+		if len(headerValues) != 2 {
+			continue
+		}
+
+		request.Header.Set(strings.TrimSpace(headerValues[0]), strings.TrimSpace(headerValues[1]))
+	}
+
+	if len(n.Secret) > 0 {
+		request.Header.Set(webhookSignatureHeader, "sha256="+signWebhookBody(n.Secret, body))
+	}
+
+	return executeJsonRequest(n.client, request)
+}
+
+func signWebhookBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *WebhookNotifier) RenderTemplate(name, text string, data model.TemplateData) (string, error) {
+	return renderJsonTemplate(name, text, data)
+}
+
+func (n *WebhookNotifier) GetId() string {
+	return n.Id
+}
+
+func (n *WebhookNotifier) IsEnabled() bool {
+	return n.Enabled
+}
+
+func (n *WebhookNotifier) GetForms() []model.NotificationForm {
+	return n.Form
+}
+
+func (n *WebhookNotifier) GetName() string {
+	return n.Name
+}
+
+func (n *WebhookNotifier) GetData() map[string]interface{} {
+	return n.Data
+}
+
+func (n *WebhookNotifier) GetServiceUpNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_UP_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultWebhookUpTemplate
+}
+
+func (n *WebhookNotifier) GetServiceDownNotificationTemplate() string {
+	if data, exists := n.Data["SERVICE_DOWN_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultWebhookDownTemplate
+}
+
+func (n *WebhookNotifier) GetDigestTemplate() string {
+	if data, exists := n.Data["DIGEST_TEMPLATE"]; exists {
+		return data.(string)
+	}
+	return defaultWebhookDigestTemplate
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestWebhookNotifier(url string, values map[string]interface{}) *WebhookNotifier {
+	store := viper.New()
+	store.Set("NOTIFIER_WEBHOOK_ENABLED", true)
+	store.Set("NOTIFIER_WEBHOOK_URL", url)
+	for k, v := range values {
+		store.Set(k, v)
+	}
+	return NewWebhookNotifier(store)
+}
+
+func TestWebhookNotifierShouldSendRenderedDownPayload(t *testing.T) {
+	var receivedBody map[string]interface{}
+	var receivedRequest *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedRequest = request
+		assert.Nil(t, json.NewDecoder(request.Body).Decode(&receivedBody))
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhookNotifier(server.URL, map[string]interface{}{
+		"NOTIFIER_WEBHOOK_HEADERS": "X-Team: sre;Authorization:Bearer abc",
+	})
+
+	data := model.TemplateData{Name: `My "quoted" Service`, Reason: "timeout", Date: "2020-12-20T10:00:00Z"}
+	message, err := RenderTemplate(webhook, webhook.GetServiceDownNotificationTemplate(), data)
+	assert.Nil(t, err)
+	assert.Nil(t, webhook.SendNotification(model.Service{}, message))
+
+	assert.Equal(t, http.MethodPost, receivedRequest.Method)
+	assert.Equal(t, "application/json", receivedRequest.Header.Get("Content-Type"))
+	assert.Equal(t, "sre", receivedRequest.Header.Get("X-Team"))
+	assert.Equal(t, "Bearer abc", receivedRequest.Header.Get("Authorization"))
+	assert.Empty(t, receivedRequest.Header.Get(webhookSignatureHeader))
+
+	assert.Equal(t, "down", receivedBody["event"])
+	assert.Equal(t, `My "quoted" Service`, receivedBody["service"])
+	assert.Equal(t, "timeout", receivedBody["reason"])
+	assert.Equal(t, "2020-12-20T10:00:00Z", receivedBody["date"])
+}
+
+func TestWebhookNotifierShouldSignBodyAndUseConfiguredMethod(t *testing.T) {
+	var receivedBody string
+	var receivedRequest *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedRequest = request
+		content, _ := ioutil.ReadAll(request.Body)
+		receivedBody = string(content)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhookNotifier(server.URL, map[string]interface{}{
+		"NOTIFIER_WEBHOOK_METHOD":              http.MethodPut,
+		"NOTIFIER_WEBHOOK_SECRET":              "top_secret",
+		"NOTIFIER_WEBHOOK_SERVICE_UP_TEMPLATE": `{"text": {{json .Name}}}`,
+	})
+
+	message, err := RenderTemplate(webhook, webhook.GetServiceUpNotificationTemplate(), model.TemplateData{Name: "api"})
+	assert.Nil(t, err)
+	assert.Nil(t, webhook.SendNotification(model.Service{}, message))
+
+	assert.Equal(t, http.MethodPut, receivedRequest.Method)
+	assert.Equal(t, `{"text": "api"}`, receivedBody)
+	assert.Equal(t, "sha256="+signWebhookBody("top_secret", receivedBody), receivedRequest.Header.Get(webhookSignatureHeader))
+}
+
+func TestWebhookNotifierShouldReturnErrorForNonSuccessStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhookNotifier(server.URL, nil)
+
+	err := webhook.SendNotification(model.Service{}, "{}")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
+
+func TestWebhookNotifierShouldRejectInvalidTemplate(t *testing.T) {
+	webhook := newTestWebhookNotifier("http://localhost", nil)
+
+	_, err := RenderTemplate(webhook, `{"text": {{json .Name}`, model.TemplateData{})
+	assert.NotNil(t, err)
+}
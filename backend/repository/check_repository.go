@@ -18,7 +18,7 @@ var (
 func prepareCheckStatements() {
 	var err error
 
-	selectChecksByServiceIdAndCreatedAtStatement, err = db.Prepare(`SELECT id, latency_in_ms, is_failure, created_at
+	selectChecksByServiceIdAndCreatedAtStatement, err = db.Prepare(`SELECT id, latency_in_ms, is_failure, is_maintenance, is_dependency_down, created_at
 																			FROM (
 																					 SELECT *, row_number() over (ORDER BY created_at DESC) AS row
 																					 FROM "check"
@@ -35,7 +35,8 @@ func prepareCheckStatements() {
 															FROM "check"
 															WHERE service_id = $1
 															  AND created_at >= $2
-															  AND created_at <= $3;`)
+															  AND created_at <= $3
+															  AND is_maintenance = false;`)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -46,13 +47,15 @@ func prepareCheckStatements() {
 														  WHERE service_id = $1
 															AND created_at >= $2
 															AND created_at <= $3
-															AND is_failure = false) as ok,
+															AND is_failure = false
+															AND is_maintenance = false) as ok,
 														 (SELECT COUNT(id) as failure
 														  FROM "check"
 														  WHERE service_id = $1
 															AND created_at >= $2
 															AND created_at <= $3
-															AND is_failure = true) as nok;`)
+															AND is_failure = true
+															AND is_maintenance = false) as nok;`)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -67,9 +70,9 @@ func prepareCheckStatements() {
 }
 
 func InsertCheck(ctx context.Context, tx *sql.Tx, check model.Check) error {
-	if _, err := tx.ExecContext(ctx, `INSERT INTO "check" (id, service_id, latency_in_ms, is_failure, created_at) 
-											VALUES ($1, $2, $3, $4, $5)`,
-		check.Id, check.ServiceId, check.LatencyInMs, check.IsFailure, check.CreatedAt); err != nil {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO "check" (id, service_id, latency_in_ms, is_failure, is_maintenance, is_dependency_down, created_at) 
+											VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		check.Id, check.ServiceId, check.LatencyInMs, check.IsFailure, check.IsMaintenance, check.IsDependencyDown, check.CreatedAt); err != nil {
 		return err
 	}
 	return nil
@@ -84,22 +87,24 @@ func SelectChecks(ctx context.Context, serviceId string, from, to time.Time, red
 
 	var id string
 	var latencyInMs int64
-	var isFailure bool
+	var isFailure, isMaintenance, isDependencyDown bool
 	var createdAt time.Time
 
 	result := make([]model.Check, 0)
 
 	for rows.Next() {
-		if err := rows.Scan(&id, &latencyInMs, &isFailure, &createdAt); err != nil {
+		if err := rows.Scan(&id, &latencyInMs, &isFailure, &isMaintenance, &isDependencyDown, &createdAt); err != nil {
 			return nil, err
 		}
 
 		result = append(result, model.Check{
-			Id:          id,
-			ServiceId:   serviceId,
-			LatencyInMs: latencyInMs,
-			IsFailure:   isFailure,
-			CreatedAt:   createdAt,
+			Id:               id,
+			ServiceId:        serviceId,
+			LatencyInMs:      latencyInMs,
+			IsFailure:        isFailure,
+			IsMaintenance:    isMaintenance,
+			IsDependencyDown: isDependencyDown,
+			CreatedAt:        createdAt,
 		})
 	}
 
@@ -126,6 +131,16 @@ func SelectUptime(ctx context.Context, serviceId string, from, to time.Time) (fl
 	return success, failures, nil
 }
 
+func SelectUptimeTx(ctx context.Context, tx *sql.Tx, serviceId string, from, to time.Time) (float64, float64, error) {
+	row := tx.StmtContext(ctx, selectUptimeStatement).QueryRowContext(ctx, serviceId, from, to)
+
+	var success, failures float64
+	if err := row.Scan(&success, &failures); err != nil {
+		return 0, 0, err
+	}
+	return success, failures, nil
+}
+
 func SelectIsOnline(ctx context.Context, serviceId string) (bool, error) {
 	row := selectOnlineStatement.QueryRowContext(ctx, serviceId)
 
@@ -137,9 +152,11 @@ func SelectIsOnline(ctx context.Context, serviceId string) (bool, error) {
 }
 
 func GetLastNChecksTx(ctx context.Context, tx *sql.Tx, serviceId string, numberOfEntries int) ([]model.Check, error) {
-	rows, err := tx.QueryContext(ctx, `SELECT id, latency_in_ms, is_failure, created_at 
+	rows, err := tx.QueryContext(ctx, `SELECT id, latency_in_ms, is_failure, is_maintenance, is_dependency_down, created_at 
 								FROM "check" 
 								WHERE service_id = $1
+								  AND is_maintenance = false
+								  AND is_dependency_down = false
 								ORDER BY created_at DESC
 								LIMIT $2;`, serviceId, numberOfEntries)
 	if err != nil {
@@ -149,24 +166,74 @@ func GetLastNChecksTx(ctx context.Context, tx *sql.Tx, serviceId string, numberO
 
 	var id string
 	var latencyInMs int64
-	var isFailure bool
+	var isFailure, isMaintenance, isDependencyDown bool
 	var createdAt time.Time
 
 	result := make([]model.Check, 0)
 
 	for rows.Next() {
-		if err := rows.Scan(&id, &latencyInMs, &isFailure, &createdAt); err != nil {
+		if err := rows.Scan(&id, &latencyInMs, &isFailure, &isMaintenance, &isDependencyDown, &createdAt); err != nil {
 			return nil, err
 		}
 
 		result = append(result, model.Check{
-			Id:          id,
-			ServiceId:   serviceId,
-			LatencyInMs: latencyInMs,
-			IsFailure:   isFailure,
-			CreatedAt:   createdAt,
+			Id:               id,
+			ServiceId:        serviceId,
+			LatencyInMs:      latencyInMs,
+			IsFailure:        isFailure,
+			IsMaintenance:    isMaintenance,
+			IsDependencyDown: isDependencyDown,
+			CreatedAt:        createdAt,
 		})
 	}
 
 	return result, nil
 }
+
+// SelectLastLatenciesTx returns the latencies of the last successful checks of the service and the time of the
+// oldest of them.
+func SelectLastLatenciesTx(ctx context.Context, tx *sql.Tx, serviceId string, numberOfEntries int) ([]int64, time.Time, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT latency_in_ms, created_at
+								FROM "check"
+								WHERE service_id = $1
+								  AND is_failure = false
+								  AND is_maintenance = false
+								ORDER BY created_at DESC
+								LIMIT $2;`, serviceId, numberOfEntries)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var latencyInMs int64
+	var createdAt, oldest time.Time
+
+	result := make([]int64, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&latencyInMs, &createdAt); err != nil {
+			return nil, time.Time{}, err
+		}
+
+		result = append(result, latencyInMs)
+		oldest = createdAt
+	}
+
+	return result, oldest, nil
+}
+
+func SelectLatencyBaselineTx(ctx context.Context, tx *sql.Tx, serviceId string, from, to time.Time) (model.LatencyBaseline, error) {
+	row := tx.QueryRowContext(ctx, `SELECT COALESCE(AVG(latency_in_ms), 0), COALESCE(STDDEV_POP(latency_in_ms), 0), COUNT(id)
+								FROM "check"
+								WHERE service_id = $1
+								  AND created_at >= $2
+								  AND created_at < $3
+								  AND is_failure = false
+								  AND is_maintenance = false;`, serviceId, from, to)
+
+	var baseline model.LatencyBaseline
+	if err := row.Scan(&baseline.Mean, &baseline.StdDev, &baseline.Count); err != nil {
+		return model.LatencyBaseline{}, err
+	}
+	return baseline, nil
+}
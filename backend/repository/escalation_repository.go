@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"time"
+)
+
+const (
+	insertEscalationPolicyQuery = `INSERT INTO escalation_policy (id, name, steps, created_at, updated_at)
+									VALUES ($1, $2, $3, $4, $5);`
+	selectEscalationPoliciesQuery = `SELECT id, name, steps, created_at, updated_at
+										FROM escalation_policy
+										ORDER BY name;`
+	selectEscalationPolicyByIdQuery = `SELECT id, name, steps, created_at, updated_at
+										FROM escalation_policy
+										WHERE id::varchar = $1;`
+	updateEscalationPolicyByIdQuery = `UPDATE escalation_policy
+										SET name=$2,
+											steps=$3,
+											updated_at=$4
+										WHERE id::varchar = $1;`
+	deleteEscalationPolicyByIdQuery = `DELETE FROM escalation_policy WHERE id::varchar = $1;`
+)
+
+func scanEscalationPolicy(row rowScanner) (model.EscalationPolicy, error) {
+	var id, name string
+	var steps []byte
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &name, &steps, &createdAt, &updatedAt); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	policy := model.EscalationPolicy{
+		Id:        id,
+		Name:      name,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+
+	if err := json.Unmarshal(steps, &policy.Steps); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+	return policy, nil
+}
+
+func InsertEscalationPolicy(ctx context.Context, policy model.EscalationPolicy) error {
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, insertEscalationPolicyQuery, policy.Id, policy.Name, steps, policy.CreatedAt,
+		policy.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SelectEscalationPolicies(ctx context.Context) ([]model.EscalationPolicy, error) {
+	rows, err := db.QueryContext(ctx, selectEscalationPoliciesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.EscalationPolicy, 0)
+
+	for rows.Next() {
+		policy, err := scanEscalationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, policy)
+	}
+	return result, nil
+}
+
+func SelectEscalationPolicyById(ctx context.Context, id string) (model.EscalationPolicy, error) {
+	return scanEscalationPolicy(db.QueryRowContext(ctx, selectEscalationPolicyByIdQuery, id))
+}
+
+func SelectEscalationPolicyByIdTx(ctx context.Context, tx *sql.Tx, id string) (model.EscalationPolicy, error) {
+	return scanEscalationPolicy(tx.QueryRowContext(ctx, selectEscalationPolicyByIdQuery, id))
+}
+
+func UpdateEscalationPolicyById(ctx context.Context, id string, policy model.EscalationPolicy) error {
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, updateEscalationPolicyByIdQuery, id, policy.Name, steps, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func DeleteEscalationPolicyById(ctx context.Context, id string) error {
+	if _, err := db.ExecContext(ctx, deleteEscalationPolicyByIdQuery, id); err != nil {
+		return err
+	}
+	return nil
+}
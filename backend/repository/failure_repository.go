@@ -16,7 +16,7 @@ var (
 
 func prepareFailureStatements() {
 	var err error
-	selectFailuresByServiceIdAndCreateAtStatement, err = db.Prepare(`SELECT id, reason, created_at
+	selectFailuresByServiceIdAndCreateAtStatement, err = db.Prepare(`SELECT id, COALESCE(incident_id::varchar, ''), reason, created_at
 																			FROM failure
 																			WHERE service_id = $1
 																			  AND created_at >= $2
@@ -53,14 +53,31 @@ func prepareFailureStatements() {
 }
 
 func InsertFailure(ctx context.Context, tx *sql.Tx, failure model.Failure) error {
-	if _, err := tx.ExecContext(ctx, `INSERT INTO failure (id, service_id, reason, created_at) 
-											VALUES ($1, $2, $3, $4)`,
-		failure.Id, failure.ServiceId, failure.Reason, failure.CreatedAt); err != nil {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO failure (id, service_id, incident_id, reason, created_at) 
+											VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)`,
+		failure.Id, failure.ServiceId, failure.IncidentId, failure.Reason, failure.CreatedAt); err != nil {
 		return err
 	}
 	return nil
 }
 
+// UpdateFailuresIncidentIdTx assigns the failures of the service since the date that do not belong to an incident yet
+// to the incident. It returns the number of assigned failures.
+func UpdateFailuresIncidentIdTx(ctx context.Context, tx *sql.Tx, serviceId, incidentId string, since time.Time) (int, error) {
+	result, err := tx.ExecContext(ctx, `UPDATE failure
+											SET incident_id = $2
+											WHERE service_id = $1
+											  AND incident_id IS NULL
+											  AND created_at >= $3`,
+		serviceId, incidentId, since)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
 func SelectFailures(ctx context.Context, serviceId string, from, to time.Time, limit, offset int) ([]model.Failure, error) {
 	rows, err := selectFailuresByServiceIdAndCreateAtStatement.QueryContext(ctx, serviceId, from, to, limit, offset)
 	if err != nil {
@@ -68,21 +85,22 @@ func SelectFailures(ctx context.Context, serviceId string, from, to time.Time, l
 	}
 	defer rows.Close()
 
-	var id, reason string
+	var id, incidentId, reason string
 	var createdAt time.Time
 
 	result := make([]model.Failure, 0)
 
 	for rows.Next() {
-		if err := rows.Scan(&id, &reason, &createdAt); err != nil {
+		if err := rows.Scan(&id, &incidentId, &reason, &createdAt); err != nil {
 			return nil, err
 		}
 
 		result = append(result, model.Failure{
-			Id:        id,
-			ServiceId: serviceId,
-			Reason:    reason,
-			CreatedAt: createdAt,
+			Id:         id,
+			ServiceId:  serviceId,
+			IncidentId: incidentId,
+			Reason:     reason,
+			CreatedAt:  createdAt,
 		})
 	}
 
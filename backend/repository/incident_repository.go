@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/koloo91/monhttp/model"
+	"time"
+)
+
+const (
+	selectIncidentColumns = `id, service_id, reason, escalation_policy_id, escalation_step, next_escalation_at,
+							 acknowledged_at, acknowledged_by, resolved_at,
+							 (SELECT COUNT(failure.id) FROM failure WHERE failure.incident_id = incident.id),
+							 created_at, updated_at`
+
+	insertIncidentQuery = `INSERT INTO incident (id, service_id, reason, escalation_policy_id, escalation_step,
+												 next_escalation_at, acknowledged_at, acknowledged_by, resolved_at,
+												 created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
+	selectOpenIncidentByServiceIdQuery = `SELECT ` + selectIncidentColumns + `
+											FROM incident
+											WHERE service_id = $1
+											  AND resolved_at IS NULL
+											FOR UPDATE;`
+	selectIncidentByIdQuery = `SELECT ` + selectIncidentColumns + `
+								FROM incident
+								WHERE id::varchar = $1;`
+	selectIncidentByIdForUpdateQuery = `SELECT ` + selectIncidentColumns + `
+											FROM incident
+											WHERE id::varchar = $1
+											FOR UPDATE;`
+	selectIncidentByIdLockedQuery = `SELECT ` + selectIncidentColumns + `
+										FROM incident
+										WHERE id = $1
+										FOR UPDATE SKIP LOCKED;`
+	selectDueIncidentIdsQuery = `SELECT id
+									FROM incident
+									WHERE resolved_at IS NULL
+									  AND acknowledged_at IS NULL
+									  AND next_escalation_at <= now()
+									ORDER BY next_escalation_at
+									LIMIT $1;`
+	updateIncidentQuery = `UPDATE incident
+							SET escalation_policy_id=$2,
+								escalation_step=$3,
+								next_escalation_at=$4,
+								acknowledged_at=$5,
+								acknowledged_by=$6,
+								resolved_at=$7,
+								updated_at=$8
+							WHERE id = $1;`
+	selectIncidentsQuery = `SELECT ` + selectIncidentColumns + `
+							FROM incident
+							WHERE ($1 = '' OR service_id::varchar = $1)
+							  AND ($2 = '' OR ($2 = 'OPEN' AND resolved_at IS NULL) OR ($2 = 'RESOLVED' AND resolved_at IS NOT NULL))
+							ORDER BY created_at DESC
+							LIMIT $3
+							OFFSET $4;`
+	selectIncidentsCountQuery = `SELECT COUNT(id)
+									FROM incident
+									WHERE ($1 = '' OR service_id::varchar = $1)
+									  AND ($2 = '' OR ($2 = 'OPEN' AND resolved_at IS NULL) OR ($2 = 'RESOLVED' AND resolved_at IS NOT NULL));`
+	selectIncidentStatisticsQuery = `SELECT COUNT(id),
+											COUNT(resolved_at),
+											COALESCE(EXTRACT(EPOCH FROM AVG(acknowledged_at - created_at)), 0),
+											COALESCE(EXTRACT(EPOCH FROM AVG(resolved_at - created_at)), 0)
+										FROM incident
+										WHERE ($1 = '' OR service_id::varchar = $1)
+										  AND created_at >= $2
+										  AND created_at <= $3;`
+
+	insertIncidentNoteQuery = `INSERT INTO incident_note (id, incident_id, text, author, created_at)
+								VALUES ($1, $2, $3, $4, $5);`
+	selectIncidentNotesQuery = `SELECT id, incident_id, text, author, created_at
+								FROM incident_note
+								WHERE incident_id = $1
+								ORDER BY created_at;`
+)
+
+func scanIncident(row rowScanner) (model.Incident, error) {
+	var id, serviceId, reason, escalationPolicyId, acknowledgedBy string
+	var escalationStep, failureCount int
+	var nextEscalationAt, acknowledgedAt, resolvedAt sql.NullTime
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &serviceId, &reason, &escalationPolicyId, &escalationStep, &nextEscalationAt,
+		&acknowledgedAt, &acknowledgedBy, &resolvedAt, &failureCount, &createdAt, &updatedAt); err != nil {
+		return model.Incident{}, err
+	}
+
+	incident := model.Incident{
+		Id:                 id,
+		ServiceId:          serviceId,
+		Reason:             reason,
+		EscalationPolicyId: escalationPolicyId,
+		EscalationStep:     escalationStep,
+		AcknowledgedBy:     acknowledgedBy,
+		FailureCount:       failureCount,
+		Notes:              make([]model.IncidentNote, 0),
+		CreatedAt:          createdAt,
+		UpdatedAt:          updatedAt,
+	}
+
+	if nextEscalationAt.Valid {
+		incident.NextEscalationAt = &nextEscalationAt.Time
+	}
+	if acknowledgedAt.Valid {
+		incident.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+	return incident, nil
+}
+
+func InsertIncidentTx(ctx context.Context, tx *sql.Tx, incident model.Incident) error {
+	if _, err := tx.ExecContext(ctx, insertIncidentQuery, incident.Id, incident.ServiceId, incident.Reason,
+		incident.EscalationPolicyId, incident.EscalationStep, incident.NextEscalationAt, incident.AcknowledgedAt,
+		incident.AcknowledgedBy, incident.ResolvedAt, incident.CreatedAt, incident.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectOpenIncidentByServiceIdTx locks and returns the unresolved incident of the service.
+func SelectOpenIncidentByServiceIdTx(ctx context.Context, tx *sql.Tx, serviceId string) (model.Incident, error) {
+	return scanIncident(tx.QueryRowContext(ctx, selectOpenIncidentByServiceIdQuery, serviceId))
+}
+
+func SelectIncidentById(ctx context.Context, id string) (model.Incident, error) {
+	return scanIncident(db.QueryRowContext(ctx, selectIncidentByIdQuery, id))
+}
+
+// SelectIncidentByIdForUpdateTx locks and returns the incident. It waits for other transactions holding the lock.
+func SelectIncidentByIdForUpdateTx(ctx context.Context, tx *sql.Tx, id string) (model.Incident, error) {
+	return scanIncident(tx.QueryRowContext(ctx, selectIncidentByIdForUpdateQuery, id))
+}
+
+// SelectIncidentByIdLockedTx locks the incident. sql.ErrNoRows is returned if it is locked by another transaction.
+func SelectIncidentByIdLockedTx(ctx context.Context, tx *sql.Tx, id string) (model.Incident, error) {
+	return scanIncident(tx.QueryRowContext(ctx, selectIncidentByIdLockedQuery, id))
+}
+
+func SelectDueIncidentIds(ctx context.Context, limit int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, selectDueIncidentIdsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var id string
+
+	result := make([]string, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+func UpdateIncidentTx(ctx context.Context, tx *sql.Tx, incident model.Incident) error {
+	if _, err := tx.ExecContext(ctx, updateIncidentQuery, incident.Id, incident.EscalationPolicyId,
+		incident.EscalationStep, incident.NextEscalationAt, incident.AcknowledgedAt, incident.AcknowledgedBy,
+		incident.ResolvedAt, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SelectIncidents(ctx context.Context, serviceId, status string, limit, offset int) ([]model.Incident, error) {
+	rows, err := db.QueryContext(ctx, selectIncidentsQuery, serviceId, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.Incident, 0)
+
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, incident)
+	}
+	return result, nil
+}
+
+func SelectIncidentsCount(ctx context.Context, serviceId, status string) (int, error) {
+	row := db.QueryRowContext(ctx, selectIncidentsCountQuery, serviceId, status)
+
+	var count int
+
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func SelectIncidentStatistics(ctx context.Context, serviceId string, from, to time.Time) (model.IncidentStatistics, error) {
+	row := db.QueryRowContext(ctx, selectIncidentStatisticsQuery, serviceId, from, to)
+
+	var count, resolvedCount int
+	var meanTimeToAcknowledge, meanTimeToRecover float64
+
+	if err := row.Scan(&count, &resolvedCount, &meanTimeToAcknowledge, &meanTimeToRecover); err != nil {
+		return model.IncidentStatistics{}, err
+	}
+
+	return model.IncidentStatistics{
+		Count:                 count,
+		ResolvedCount:         resolvedCount,
+		MeanTimeToAcknowledge: time.Duration(meanTimeToAcknowledge * float64(time.Second)),
+		MeanTimeToRecover:     time.Duration(meanTimeToRecover * float64(time.Second)),
+	}, nil
+}
+
+func InsertIncidentNote(ctx context.Context, note model.IncidentNote) error {
+	if _, err := db.ExecContext(ctx, insertIncidentNoteQuery, note.Id, note.IncidentId, note.Text, note.Author,
+		note.CreatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SelectIncidentNotes(ctx context.Context, incidentId string) ([]model.IncidentNote, error) {
+	rows, err := db.QueryContext(ctx, selectIncidentNotesQuery, incidentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var id, text, author string
+	var createdAt time.Time
+
+	result := make([]model.IncidentNote, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&id, &incidentId, &text, &author, &createdAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, model.IncidentNote{
+			Id:         id,
+			IncidentId: incidentId,
+			Text:       text,
+			Author:     author,
+			CreatedAt:  createdAt,
+		})
+	}
+	return result, nil
+}
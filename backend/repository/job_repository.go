@@ -8,8 +8,12 @@ import (
 )
 
 const (
-	insertJobQuery           = `INSERT INTO job(id, service_id, execute_at, created_at, updated_at) VALUES($1, $2, $3, $4, $5);`
-	getNextJobIdsQuery       = `SELECT id FROM job WHERE execute_at <= now();`
+	insertJobQuery     = `INSERT INTO job(id, service_id, execute_at, created_at, updated_at) VALUES($1, $2, $3, $4, $5);`
+	getNextJobIdsQuery = `SELECT job.id
+								FROM job
+										 JOIN service ON service.id = job.service_id
+								WHERE job.execute_at <= now()
+								  AND service.enabled = true;`
 	selectJobByIdLockedQuery = `SELECT service_id,
 									   execute_at,
 									   created_at,
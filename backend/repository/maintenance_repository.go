@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"github.com/koloo91/monhttp/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	insertMaintenanceQuery = `INSERT INTO maintenance (id, name, description, start_at, end_at, recurrence, skip_checks,
+														service_ids, tags, created_at, updated_at)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
+	selectMaintenancesQuery = `SELECT id, name, description, start_at, end_at, recurrence, skip_checks, service_ids, tags,
+									  created_at, updated_at
+								FROM maintenance
+								ORDER BY start_at DESC;`
+	selectMaintenanceByIdQuery = `SELECT id, name, description, start_at, end_at, recurrence, skip_checks, service_ids, tags,
+										 created_at, updated_at
+									FROM maintenance
+									WHERE id = $1;`
+	selectStartedMaintenancesForServiceQuery = `SELECT id, name, description, start_at, end_at, recurrence, skip_checks,
+													   service_ids, tags, created_at, updated_at
+												FROM maintenance
+												WHERE start_at <= $3
+												  AND ($1 = ANY (service_ids) OR tags && $2);`
+	updateMaintenanceByIdQuery = `UPDATE maintenance
+									SET name=$2,
+										description=$3,
+										start_at=$4,
+										end_at=$5,
+										recurrence=$6,
+										skip_checks=$7,
+										service_ids=$8,
+										tags=$9,
+										updated_at=$10
+									WHERE id = $1;`
+	deleteMaintenanceByIdQuery = `DELETE FROM maintenance WHERE id = $1;`
+)
+
+func scanMaintenance(row rowScanner) (model.Maintenance, error) {
+	var id, name, description string
+	var recurrence model.MaintenanceRecurrence
+	var skipChecks bool
+	var serviceIds, tags []string
+	var startAt, endAt, createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &name, &description, &startAt, &endAt, &recurrence, &skipChecks,
+		pq.Array(&serviceIds), pq.Array(&tags), &createdAt, &updatedAt); err != nil {
+		return model.Maintenance{}, err
+	}
+
+	return model.Maintenance{
+		Id:          id,
+		Name:        name,
+		Description: description,
+		StartAt:     startAt,
+		EndAt:       endAt,
+		Recurrence:  recurrence,
+		SkipChecks:  skipChecks,
+		ServiceIds:  serviceIds,
+		Tags:        tags,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}, nil
+}
+
+func InsertMaintenance(ctx context.Context, maintenance model.Maintenance) error {
+	if _, err := db.ExecContext(ctx, insertMaintenanceQuery, maintenance.Id, maintenance.Name, maintenance.Description,
+		maintenance.StartAt, maintenance.EndAt, maintenance.Recurrence, maintenance.SkipChecks,
+		pq.Array(maintenance.ServiceIds), pq.Array(maintenance.Tags), maintenance.CreatedAt, maintenance.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SelectMaintenances(ctx context.Context) ([]model.Maintenance, error) {
+	rows, err := db.QueryContext(ctx, selectMaintenancesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.Maintenance, 0)
+
+	for rows.Next() {
+		maintenance, err := scanMaintenance(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, maintenance)
+	}
+	return result, nil
+}
+
+func SelectMaintenanceById(ctx context.Context, id string) (model.Maintenance, error) {
+	return scanMaintenance(db.QueryRowContext(ctx, selectMaintenanceByIdQuery, id))
+}
+
+// SelectStartedMaintenancesForService returns all windows attached to the service or one of its tags that started
+// before the given time. Whether a window is still active has to be checked with model.Maintenance.IsActiveAt.
+func SelectStartedMaintenancesForService(ctx context.Context, serviceId string, tags []string, at time.Time) ([]model.Maintenance, error) {
+	rows, err := db.QueryContext(ctx, selectStartedMaintenancesForServiceQuery, serviceId, pq.Array(tags), at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.Maintenance, 0)
+
+	for rows.Next() {
+		maintenance, err := scanMaintenance(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, maintenance)
+	}
+	return result, nil
+}
+
+func UpdateMaintenanceById(ctx context.Context, id string, maintenance model.Maintenance) error {
+	if _, err := db.ExecContext(ctx, updateMaintenanceByIdQuery, id, maintenance.Name, maintenance.Description,
+		maintenance.StartAt, maintenance.EndAt, maintenance.Recurrence, maintenance.SkipChecks,
+		pq.Array(maintenance.ServiceIds), pq.Array(maintenance.Tags), time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteMaintenanceById(ctx context.Context, id string) error {
+	if _, err := db.ExecContext(ctx, deleteMaintenanceByIdQuery, id); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	selectNotificationColumns = `id, COALESCE(service_id::varchar, ''), service_name, notifier_id, is_up_notification,
+								 is_degraded_notification, reason, details, payload, status, is_digest, COALESCE(digest_id::varchar, ''), attempts,
+								 last_error, output, next_attempt_at, sent_at, created_at, updated_at`
+
+	insertNotificationQuery = `INSERT INTO notification (id, service_id, service_name, notifier_id, is_up_notification,
+															is_degraded_notification, reason, details, payload, status,
+															is_digest, attempts, last_error, next_attempt_at, sent_at,
+															created_at, updated_at)
+								VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+										$17);`
+	skipPendingNotificationsQuery = `UPDATE notification
+										SET status=$3,
+											last_error='superseded by a newer notification',
+											updated_at=now()
+										WHERE service_id = $1
+										  AND notifier_id = $2
+										  AND status = $4
+										  AND is_up_notification = $5
+										  AND is_degraded_notification = $6;`
+	selectPendingPredecessorNextAttemptAtQuery = `SELECT MAX(next_attempt_at)
+													FROM notification
+													WHERE service_id = $1
+													  AND notifier_id = $2
+													  AND status = $3
+													  AND created_at < $4;`
+	selectDueNotificationIdsQuery = `SELECT id
+										FROM notification
+										WHERE status = $1
+										  AND next_attempt_at <= now()
+										ORDER BY next_attempt_at
+										LIMIT $2;`
+	selectNotificationByIdLockedQuery = `SELECT ` + selectNotificationColumns + `
+											FROM notification
+											WHERE id = $1
+											FOR UPDATE SKIP LOCKED;`
+	updateNotificationDeliveryQuery = `UPDATE notification
+										SET status=$2,
+											attempts=$3,
+											last_error=$4,
+											output=$5,
+											next_attempt_at=$6,
+											sent_at=$7,
+											updated_at=$8
+										WHERE id = $1;`
+	selectSentNotificationsCountQuery = `SELECT COUNT(id)
+											FROM notification
+											WHERE ($1 = '' OR notifier_id = $1)
+											  AND status = $2
+											  AND sent_at > $3;`
+	selectUndigestedNotifierIdsQuery = `SELECT notifier_id
+										FROM notification
+										WHERE status = $1
+										  AND digest_id IS NULL
+										GROUP BY notifier_id
+										HAVING MIN(updated_at) <= $2;`
+	selectUndigestedNotificationsLockedQuery = `SELECT ` + selectNotificationColumns + `
+												FROM notification
+												WHERE notifier_id = $1
+												  AND status = $2
+												  AND digest_id IS NULL
+												ORDER BY created_at
+												FOR UPDATE SKIP LOCKED;`
+	releaseHeldNotificationsQuery = `UPDATE notification
+										SET status=$2,
+											next_attempt_at=now(),
+											updated_at=now()
+										WHERE notifier_id = $1
+										  AND status = $3
+										  AND digest_id IS NULL;`
+	updateNotificationDigestIdQuery = `UPDATE notification
+										SET digest_id=$2,
+											updated_at=now()
+										WHERE id = ANY ($1::uuid[]);`
+	selectNotificationsQuery = `SELECT ` + selectNotificationColumns + `
+								FROM notification
+								WHERE ($1 = '' OR service_id::varchar = $1)
+								  AND ($2 = '' OR notifier_id = $2)
+								  AND ($3 = '' OR status = $3)
+								ORDER BY created_at DESC
+								LIMIT $4
+								OFFSET $5;`
+	selectNotificationsCountQuery = `SELECT COUNT(id)
+										FROM notification
+										WHERE ($1 = '' OR service_id::varchar = $1)
+										  AND ($2 = '' OR notifier_id = $2)
+										  AND ($3 = '' OR status = $3);`
+)
+
+func scanNotification(row rowScanner) (model.Notification, error) {
+	var id, serviceId, serviceName, notifierId, reason, payload, digestId, lastError, output string
+	var status model.NotificationStatus
+	var isUpNotification, isDegradedNotification, isDigest bool
+	var attempts int
+	var nextAttemptAt, createdAt, updatedAt time.Time
+	var sentAt sql.NullTime
+	var detailsData []byte
+
+	if err := row.Scan(&id, &serviceId, &serviceName, &notifierId, &isUpNotification, &isDegradedNotification, &reason,
+		&detailsData, &payload, &status, &isDigest, &digestId, &attempts, &lastError, &output, &nextAttemptAt, &sentAt,
+		&createdAt, &updatedAt); err != nil {
+		return model.Notification{}, err
+	}
+
+	// notifications queued before the details were stored only have the reason
+	details := model.NotificationDetails{Reason: reason, IsDegraded: isDegradedNotification}
+	if detailsData != nil {
+		if err := json.Unmarshal(detailsData, &details); err != nil {
+			return model.Notification{}, err
+		}
+	}
+
+	notification := model.Notification{
+		Id:                     id,
+		ServiceId:              serviceId,
+		ServiceName:            serviceName,
+		NotifierId:             notifierId,
+		IsUpNotification:       isUpNotification,
+		IsDegradedNotification: isDegradedNotification,
+		Reason:                 reason,
+		Details:                details,
+		Payload:                payload,
+		Status:                 status,
+		IsDigest:               isDigest,
+		DigestId:               digestId,
+		Attempts:               attempts,
+		LastError:              lastError,
+		Output:                 output,
+		NextAttemptAt:          nextAttemptAt,
+		CreatedAt:              createdAt,
+		UpdatedAt:              updatedAt,
+	}
+
+	if sentAt.Valid {
+		notification.SentAt = &sentAt.Time
+	}
+	return notification, nil
+}
+
+func InsertNotificationTx(ctx context.Context, tx *sql.Tx, notification model.Notification) error {
+	details, err := marshalNotificationDetails(notification)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, insertNotificationQuery, notification.Id, notification.ServiceId, notification.ServiceName,
+		notification.NotifierId, notification.IsUpNotification, notification.IsDegradedNotification, notification.Reason,
+		details, notification.Payload, notification.Status, notification.IsDigest, notification.Attempts, notification.LastError,
+		notification.NextAttemptAt, notification.SentAt, notification.CreatedAt, notification.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+// marshalNotificationDetails stores the details of digests as null.
+func marshalNotificationDetails(notification model.Notification) (sql.NullString, error) {
+	if notification.IsDigest {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(notification.Details)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// SkipPendingNotificationsTx marks the pending notifications of the service and notifier that the notification
+// supersedes as skipped. Only notifications of the same state are superseded, so that an undelivered down
+// notification is not replaced by the up notification.
+func SkipPendingNotificationsTx(ctx context.Context, tx *sql.Tx, notification model.Notification) error {
+	if _, err := tx.ExecContext(ctx, skipPendingNotificationsQuery, notification.ServiceId, notification.NotifierId,
+		model.NotificationStatusSkipped, model.NotificationStatusPending, notification.IsUpNotification,
+		notification.IsDegradedNotification); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectPendingPredecessorNextAttemptAtTx returns the latest next attempt of the pending notifications of the same
+// service and notifier that were queued before the notification. It returns nil if there are none.
+func SelectPendingPredecessorNextAttemptAtTx(ctx context.Context, tx *sql.Tx, notification model.Notification) (*time.Time, error) {
+	var nextAttemptAt sql.NullTime
+	if err := tx.QueryRowContext(ctx, selectPendingPredecessorNextAttemptAtQuery, notification.ServiceId,
+		notification.NotifierId, model.NotificationStatusPending, notification.CreatedAt).Scan(&nextAttemptAt); err != nil {
+		return nil, err
+	}
+
+	if !nextAttemptAt.Valid {
+		return nil, nil
+	}
+	return &nextAttemptAt.Time, nil
+}
+
+func SelectDueNotificationIds(ctx context.Context, limit int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, selectDueNotificationIdsQuery, model.NotificationStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var id string
+
+	result := make([]string, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// SelectNotificationByIdLockedTx locks the notification. sql.ErrNoRows is returned if it is locked by another
+// transaction.
+func SelectNotificationByIdLockedTx(ctx context.Context, tx *sql.Tx, id string) (model.Notification, error) {
+	return scanNotification(tx.QueryRowContext(ctx, selectNotificationByIdLockedQuery, id))
+}
+
+func UpdateNotificationDeliveryTx(ctx context.Context, tx *sql.Tx, notification model.Notification) error {
+	if _, err := tx.ExecContext(ctx, updateNotificationDeliveryQuery, notification.Id, notification.Status,
+		notification.Attempts, notification.LastError, notification.Output, notification.NextAttemptAt,
+		notification.SentAt, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectSentNotificationsCountTx returns the number of notifications sent since the given time. An empty notifier id
+// counts the notifications of all notifiers.
+func SelectSentNotificationsCountTx(ctx context.Context, tx *sql.Tx, notifierId string, since time.Time) (int, error) {
+	row := tx.QueryRowContext(ctx, selectSentNotificationsCountQuery, notifierId, model.NotificationStatusSent, since)
+
+	var count int
+
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SelectDueDigestNotifierIds returns the notifiers with coalesced notifications that were coalesced before the given
+// time and are not part of a digest yet.
+func SelectDueDigestNotifierIds(ctx context.Context, coalescedBefore time.Time) ([]string, error) {
+	return selectUndigestedNotifierIds(ctx, model.NotificationStatusCoalesced, coalescedBefore)
+}
+
+func selectUndigestedNotifierIds(ctx context.Context, status model.NotificationStatus, before time.Time) ([]string, error) {
+	rows, err := db.QueryContext(ctx, selectUndigestedNotifierIdsQuery, status, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifierId string
+
+	result := make([]string, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&notifierId); err != nil {
+			return nil, err
+		}
+
+		result = append(result, notifierId)
+	}
+	return result, nil
+}
+
+// SelectHeldNotifierIds returns the notifiers with held notifications that are not part of a digest yet.
+func SelectHeldNotifierIds(ctx context.Context) ([]string, error) {
+	return selectUndigestedNotifierIds(ctx, model.NotificationStatusHeld, time.Now())
+}
+
+// SelectUndigestedNotificationsLockedTx locks the coalesced or held notifications of the notifier that are not part
+// of a digest yet. Notifications locked by another transaction are skipped.
+func SelectUndigestedNotificationsLockedTx(ctx context.Context, tx *sql.Tx, notifierId string,
+	status model.NotificationStatus) ([]model.Notification, error) {
+	rows, err := tx.QueryContext(ctx, selectUndigestedNotificationsLockedQuery, notifierId, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.Notification, 0)
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, notification)
+	}
+	return result, nil
+}
+
+// ReleaseHeldNotifications turns the held notifications of the notifier into pending ones that are sent right away.
+func ReleaseHeldNotifications(ctx context.Context, notifierId string) error {
+	if _, err := db.ExecContext(ctx, releaseHeldNotificationsQuery, notifierId, model.NotificationStatusPending,
+		model.NotificationStatusHeld); err != nil {
+		return err
+	}
+	return nil
+}
+
+func UpdateNotificationDigestIdTx(ctx context.Context, tx *sql.Tx, ids []string, digestId string) error {
+	if _, err := tx.ExecContext(ctx, updateNotificationDigestIdQuery, pq.Array(ids), digestId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SelectNotifications(ctx context.Context, serviceId, notifierId string, status model.NotificationStatus, limit, offset int) ([]model.Notification, error) {
+	rows, err := db.QueryContext(ctx, selectNotificationsQuery, serviceId, notifierId, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.Notification, 0)
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, notification)
+	}
+	return result, nil
+}
+
+func SelectNotificationsCount(ctx context.Context, serviceId, notifierId string, status model.NotificationStatus) (int, error) {
+	row := db.QueryRowContext(ctx, selectNotificationsCountQuery, serviceId, notifierId, status)
+
+	var count int
+
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/koloo91/monhttp/model"
+	"time"
+)
+
+const (
+	insertNotifierQuery = `INSERT INTO notifier (id, type, name, data, rate_limit, schedule, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+	insertNotifierIfNotExistsQuery = `INSERT INTO notifier (id, type, name, data, rate_limit, schedule, created_at, updated_at)
+										VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+										ON CONFLICT (id) DO NOTHING;`
+	selectNotifiersQuery = `SELECT id, type, name, data, rate_limit, schedule, created_at, updated_at
+							FROM notifier
+							ORDER BY name;`
+	selectNotifierByIdQuery = `SELECT id, type, name, data, rate_limit, schedule, created_at, updated_at
+								FROM notifier
+								WHERE id = $1;`
+	updateNotifierByIdQuery = `UPDATE notifier
+								SET name=$2,
+									data=$3,
+									rate_limit=$4,
+									schedule=$5,
+									updated_at=$6
+								WHERE id = $1;`
+	deleteNotifierByIdQuery = `DELETE FROM notifier WHERE id = $1;`
+)
+
+func scanNotifier(row rowScanner) (model.NotifierInstance, error) {
+	var id, notifierType, name string
+	var data, scheduleData []byte
+	var rateLimit int
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &notifierType, &name, &data, &rateLimit, &scheduleData, &createdAt, &updatedAt); err != nil {
+		return model.NotifierInstance{}, err
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return model.NotifierInstance{}, err
+	}
+
+	var schedule *model.NotificationSchedule
+	if scheduleData != nil {
+		if err := json.Unmarshal(scheduleData, &schedule); err != nil {
+			return model.NotifierInstance{}, err
+		}
+	}
+
+	return model.NotifierInstance{
+		Id:        id,
+		Type:      notifierType,
+		Name:      name,
+		Data:      values,
+		RateLimit: rateLimit,
+		Schedule:  schedule,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func InsertNotifier(ctx context.Context, notifier model.NotifierInstance) error {
+	_, err := insertNotifier(ctx, insertNotifierQuery, notifier)
+	return err
+}
+
+// InsertNotifierIfNotExists inserts the notifier unless a notifier with its id exists. It returns true if the
+// notifier was inserted.
+func InsertNotifierIfNotExists(ctx context.Context, notifier model.NotifierInstance) (bool, error) {
+	result, err := insertNotifier(ctx, insertNotifierIfNotExistsQuery, notifier)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	return count > 0, err
+}
+
+func insertNotifier(ctx context.Context, query string, notifier model.NotifierInstance) (sql.Result, error) {
+	data, err := json.Marshal(notifier.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := marshalNotificationSchedule(notifier.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ExecContext(ctx, query, notifier.Id, notifier.Type, notifier.Name, data, notifier.RateLimit, schedule,
+		notifier.CreatedAt, notifier.UpdatedAt)
+}
+
+func SelectNotifiers(ctx context.Context) ([]model.NotifierInstance, error) {
+	rows, err := db.QueryContext(ctx, selectNotifiersQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]model.NotifierInstance, 0)
+
+	for rows.Next() {
+		notifier, err := scanNotifier(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, notifier)
+	}
+	return result, nil
+}
+
+func SelectNotifierById(ctx context.Context, id string) (model.NotifierInstance, error) {
+	return scanNotifier(db.QueryRowContext(ctx, selectNotifierByIdQuery, id))
+}
+
+func UpdateNotifierById(ctx context.Context, id string, notifier model.NotifierInstance) error {
+	data, err := json.Marshal(notifier.Data)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := marshalNotificationSchedule(notifier.Schedule)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, updateNotifierByIdQuery, id, notifier.Name, data, notifier.RateLimit, schedule,
+		time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// marshalNotificationSchedule stores notifiers without a schedule as null.
+func marshalNotificationSchedule(schedule *model.NotificationSchedule) (sql.NullString, error) {
+	if schedule == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func DeleteNotifierById(ctx context.Context, id string) error {
+	if _, err := db.ExecContext(ctx, deleteNotifierByIdQuery, id); err != nil {
+		return err
+	}
+	return nil
+}
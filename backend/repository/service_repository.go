@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"github.com/koloo91/monhttp/model"
 	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
@@ -13,8 +14,64 @@ const (
 	insertServiceQuery = `INSERT INTO service (id, name, type, interval_in_seconds, endpoint, http_method,
 											 request_timeout_in_seconds, http_headers, http_body, expected_http_response_body,
 											 expected_http_status_code, follow_redirects, verify_ssl, enable_notifications,
-											 notify_after_number_of_failures, continuously_send_notifications, notifiers, created_at, updated_at)
-						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19);`
+											 notify_after_number_of_failures, continuously_send_notifications, notifiers, tags,
+											 enabled, parent_ids, escalation_policy_id, flapping_threshold, flapping_window,
+											 notification_templates, slo_target, slo_window_in_days, slo_fast_burn_rate,
+											 slo_slow_burn_rate, slo_alerts_only, latency_threshold_in_ms, latency_percentile,
+											 latency_window, latency_deviation, created_at, updated_at)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23,
+								$24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35);`
+
+	updateServiceIsFlappingQuery = `UPDATE service
+									SET is_flapping=$2
+									WHERE id = $1;`
+
+	updateServiceSloBurnStateQuery = `UPDATE service
+										SET slo_burn_state=$2
+										WHERE id = $1;`
+
+	updateServiceIsDegradedQuery = `UPDATE service
+									SET is_degraded=$2
+									WHERE id = $1;`
+
+	selectServiceColumns = `id,
+							name,
+							type,
+							interval_in_seconds,
+							endpoint,
+							http_method,
+							request_timeout_in_seconds,
+							http_headers,
+							http_body,
+							expected_http_response_body,
+							expected_http_status_code,
+							follow_redirects,
+							verify_ssl,
+							enable_notifications,
+							notify_after_number_of_failures,
+							continuously_send_notifications,
+							notifiers,
+							tags,
+							enabled,
+							parent_ids,
+							escalation_policy_id,
+							flapping_threshold,
+							flapping_window,
+							is_flapping,
+							notification_templates,
+							slo_target,
+							slo_window_in_days,
+							slo_fast_burn_rate,
+							slo_slow_burn_rate,
+							slo_alerts_only,
+							slo_burn_state,
+							latency_threshold_in_ms,
+							latency_percentile,
+							latency_window,
+							latency_deviation,
+							is_degraded,
+							created_at,
+							updated_at`
 )
 
 var (
@@ -24,8 +81,21 @@ var (
 	selectServiceByIdStatement   *sql.Stmt
 	updateServiceByIdStatement   *sql.Stmt
 	deleteServiceByIdStatement   *sql.Stmt
+
+	updateServiceEnabledByIdStatement   *sql.Stmt
+	updateServicesEnabledByIdsStatement *sql.Stmt
+	updateAllServicesEnabledStatement   *sql.Stmt
+
+	selectServiceDependenciesStatement *sql.Stmt
+	removeParentIdStatement            *sql.Stmt
+	removeNotifierIdStatement          *sql.Stmt
+	removeEscalationPolicyIdStatement  *sql.Stmt
 )
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 func prepareServiceStatements() {
 	var err error
 
@@ -34,25 +104,7 @@ func prepareServiceStatements() {
 		log.Fatal(err)
 	}
 
-	selectServicesStatement, err = db.Prepare(`SELECT id,
-															   name,
-															   type,
-															   interval_in_seconds,
-															   endpoint,
-															   http_method,
-															   request_timeout_in_seconds,
-															   http_headers,
-															   http_body,
-															   expected_http_response_body,
-															   expected_http_status_code,
-															   follow_redirects,
-															   verify_ssl,
-															   enable_notifications,
-															   notify_after_number_of_failures,
-       														   continuously_send_notifications,
-       														   notifiers,
-															   created_at,
-															   updated_at
+	selectServicesStatement, err = db.Prepare(`SELECT ` + selectServiceColumns + `
 														FROM service
 																ORDER BY name
 																LIMIT $1 
@@ -67,25 +119,7 @@ func prepareServiceStatements() {
 		log.Fatal(err)
 	}
 
-	selectServiceByIdStatement, err = db.Prepare(`SELECT id,
-															   name,
-															   type,
-															   interval_in_seconds,
-															   endpoint,
-															   http_method,
-															   request_timeout_in_seconds,
-															   http_headers,
-															   http_body,
-															   expected_http_response_body,
-															   expected_http_status_code,
-															   follow_redirects,
-															   verify_ssl,
-															   enable_notifications,
-															   notify_after_number_of_failures,
-       														   continuously_send_notifications,
-       														   notifiers,
-															   created_at,
-															   updated_at
+	selectServiceByIdStatement, err = db.Prepare(`SELECT ` + selectServiceColumns + `
 														FROM service WHERE id = $1;`)
 	if err != nil {
 		log.Fatal(err)
@@ -108,7 +142,22 @@ func prepareServiceStatements() {
 															notify_after_number_of_failures=$15,
 														    continuously_send_notifications=$16,
 														    notifiers=$17,
-															updated_at=$18
+														    tags=$18,
+														    parent_ids=$19,
+														    escalation_policy_id=$20,
+														    flapping_threshold=$21,
+														    flapping_window=$22,
+														    notification_templates=$23,
+														    slo_target=$24,
+														    slo_window_in_days=$25,
+														    slo_fast_burn_rate=$26,
+														    slo_slow_burn_rate=$27,
+														    slo_alerts_only=$28,
+														    latency_threshold_in_ms=$29,
+														    latency_percentile=$30,
+														    latency_window=$31,
+														    latency_deviation=$32,
+															updated_at=$33
 														WHERE id = $1;`)
 	if err != nil {
 		log.Fatal(err)
@@ -120,14 +169,150 @@ func prepareServiceStatements() {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	updateServiceEnabledByIdStatement, err = db.Prepare(`UPDATE service
+																SET enabled=$2,
+																	updated_at=$3
+																WHERE id = $1;`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	updateServicesEnabledByIdsStatement, err = db.Prepare(`UPDATE service
+																SET enabled=$2,
+																	updated_at=$3
+																WHERE id = ANY ($1::uuid[]);`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	updateAllServicesEnabledStatement, err = db.Prepare(`UPDATE service
+																SET enabled=$1,
+																	updated_at=$2;`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	selectServiceDependenciesStatement, err = db.Prepare(`SELECT id, name, parent_ids
+																FROM service
+																ORDER BY name;`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	removeParentIdStatement, err = db.Prepare(`UPDATE service
+														SET parent_ids = array_remove(parent_ids, $1::varchar)
+														WHERE $1 = ANY (parent_ids);`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	removeNotifierIdStatement, err = db.Prepare(`UPDATE service
+														SET notifiers = array_remove(notifiers, $1::varchar),
+															notification_templates = (SELECT COALESCE(jsonb_agg(template), '[]')
+																					  FROM jsonb_array_elements(notification_templates) template
+																					  WHERE template ->> 'notifierId' <> $1::varchar)
+														WHERE $1::varchar = ANY (notifiers)
+														   OR notification_templates @> jsonb_build_array(jsonb_build_object('notifierId', $1::varchar));`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	removeEscalationPolicyIdStatement, err = db.Prepare(`UPDATE service
+																SET escalation_policy_id = ''
+																WHERE escalation_policy_id = $1;`)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func scanService(row rowScanner) (model.Service, error) {
+	var id, name, endpoint, httpMethod, httpHeaders, httpBody, expectedHttpResponseBody, escalationPolicyId string
+	var serviceType model.ServiceType
+	var intervalInSeconds, requestTimeoutInSeconds, expectedHttpStatusCode, notifyAfterNumberOfFailures int
+	var flappingThreshold, flappingWindow, sloWindowInDays, latencyPercentile, latencyWindow int
+	var sloTarget, sloFastBurnRate, sloSlowBurnRate, latencyDeviation float64
+	var latencyThresholdInMs int64
+	var sloAlertsOnly, isDegraded bool
+	var sloBurnState model.SloBurnState
+	var followRedirects, verifySsl, enableNotifications, continuouslySendNotifications, enabled, isFlapping bool
+	var notifiers, tags, parentIds []string
+	var notificationTemplates []byte
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &name, &serviceType, &intervalInSeconds, &endpoint, &httpMethod,
+		&requestTimeoutInSeconds, &httpHeaders, &httpBody, &expectedHttpResponseBody,
+		&expectedHttpStatusCode, &followRedirects, &verifySsl, &enableNotifications,
+		&notifyAfterNumberOfFailures, &continuouslySendNotifications, pq.Array(&notifiers), pq.Array(&tags),
+		&enabled, pq.Array(&parentIds), &escalationPolicyId, &flappingThreshold, &flappingWindow, &isFlapping,
+		&notificationTemplates, &sloTarget, &sloWindowInDays, &sloFastBurnRate, &sloSlowBurnRate, &sloAlertsOnly,
+		&sloBurnState, &latencyThresholdInMs, &latencyPercentile, &latencyWindow, &latencyDeviation, &isDegraded,
+		&createdAt, &updatedAt); err != nil {
+		return model.Service{}, err
+	}
+
+	templates := make([]model.NotificationTemplate, 0)
+	if err := json.Unmarshal(notificationTemplates, &templates); err != nil {
+		return model.Service{}, err
+	}
+
+	return model.Service{
+		Id:                            id,
+		Name:                          name,
+		Type:                          serviceType,
+		IntervalInSeconds:             intervalInSeconds,
+		Endpoint:                      endpoint,
+		HttpMethod:                    httpMethod,
+		RequestTimeoutInSeconds:       requestTimeoutInSeconds,
+		HttpHeaders:                   httpHeaders,
+		HttpBody:                      httpBody,
+		ExpectedHttpResponseBody:      expectedHttpResponseBody,
+		ExpectedHttpStatusCode:        expectedHttpStatusCode,
+		FollowRedirects:               followRedirects,
+		VerifySsl:                     verifySsl,
+		EnableNotifications:           enableNotifications,
+		NotifyAfterNumberOfFailures:   notifyAfterNumberOfFailures,
+		ContinuouslySendNotifications: continuouslySendNotifications,
+		Notifiers:                     notifiers,
+		Tags:                          tags,
+		Enabled:                       enabled,
+		ParentIds:                     parentIds,
+		EscalationPolicyId:            escalationPolicyId,
+		FlappingThreshold:             flappingThreshold,
+		FlappingWindow:                flappingWindow,
+		IsFlapping:                    isFlapping,
+		NotificationTemplates:         templates,
+		SloTarget:                     sloTarget,
+		SloWindowInDays:               sloWindowInDays,
+		SloFastBurnRate:               sloFastBurnRate,
+		SloSlowBurnRate:               sloSlowBurnRate,
+		SloAlertsOnly:                 sloAlertsOnly,
+		SloBurnState:                  sloBurnState,
+		LatencyThresholdInMs:          latencyThresholdInMs,
+		LatencyPercentile:             latencyPercentile,
+		LatencyWindow:                 latencyWindow,
+		LatencyDeviation:              latencyDeviation,
+		IsDegraded:                    isDegraded,
+		CreatedAt:                     createdAt,
+		UpdatedAt:                     updatedAt,
+	}, nil
 }
 
 func InsertService(ctx context.Context, service model.Service) error {
+	notificationTemplates, err := marshalNotificationTemplates(service.NotificationTemplates)
+	if err != nil {
+		return err
+	}
+
 	if _, err := insertServiceStatement.ExecContext(ctx,
 		service.Id, service.Name, service.Type, service.IntervalInSeconds, service.Endpoint, service.HttpMethod,
 		service.RequestTimeoutInSeconds, service.HttpHeaders, service.HttpBody, service.ExpectedHttpResponseBody,
 		service.ExpectedHttpStatusCode, service.FollowRedirects, service.VerifySsl, service.EnableNotifications,
 		service.NotifyAfterNumberOfFailures, service.ContinuouslySendNotifications, pq.Array(service.Notifiers),
+		pq.Array(service.Tags), service.Enabled, pq.Array(service.ParentIds), service.EscalationPolicyId,
+		service.FlappingThreshold, service.FlappingWindow, notificationTemplates, service.SloTarget,
+		service.SloWindowInDays, service.SloFastBurnRate, service.SloSlowBurnRate, service.SloAlertsOnly,
+		service.LatencyThresholdInMs, service.LatencyPercentile, service.LatencyWindow, service.LatencyDeviation,
 		service.CreatedAt, service.UpdatedAt); err != nil {
 		return err
 	}
@@ -136,11 +321,20 @@ func InsertService(ctx context.Context, service model.Service) error {
 }
 
 func InsertServiceTx(ctx context.Context, tx *sql.Tx, service model.Service) error {
+	notificationTemplates, err := marshalNotificationTemplates(service.NotificationTemplates)
+	if err != nil {
+		return err
+	}
+
 	if _, err := tx.ExecContext(ctx, insertServiceQuery,
 		service.Id, service.Name, service.Type, service.IntervalInSeconds, service.Endpoint, service.HttpMethod,
 		service.RequestTimeoutInSeconds, service.HttpHeaders, service.HttpBody, service.ExpectedHttpResponseBody,
 		service.ExpectedHttpStatusCode, service.FollowRedirects, service.VerifySsl, service.EnableNotifications,
 		service.NotifyAfterNumberOfFailures, service.ContinuouslySendNotifications, pq.Array(service.Notifiers),
+		pq.Array(service.Tags), service.Enabled, pq.Array(service.ParentIds), service.EscalationPolicyId,
+		service.FlappingThreshold, service.FlappingWindow, notificationTemplates, service.SloTarget,
+		service.SloWindowInDays, service.SloFastBurnRate, service.SloSlowBurnRate, service.SloAlertsOnly,
+		service.LatencyThresholdInMs, service.LatencyPercentile, service.LatencyWindow, service.LatencyDeviation,
 		service.CreatedAt, service.UpdatedAt); err != nil {
 		return err
 	}
@@ -156,45 +350,15 @@ func SelectServices(ctx context.Context, limit, offset int) ([]model.Service, er
 
 	defer rows.Close()
 
-	var id, name, endpoint, httpMethod, httpHeaders, httpBody, expectedHttpResponseBody string
-	var serviceType model.ServiceType
-	var intervalInSeconds, requestTimeoutInSeconds, expectedHttpStatusCode, notifyAfterNumberOfFailures int
-	var followRedirects, verifySsl, enableNotifications, continuouslySendNotifications bool
-	var notifiers []string
-	var createdAt, updatedAt time.Time
-
 	result := make([]model.Service, 0)
 
 	for rows.Next() {
-		if err := rows.Scan(&id, &name, &serviceType, &intervalInSeconds, &endpoint, &httpMethod,
-			&requestTimeoutInSeconds, &httpHeaders, &httpBody, &expectedHttpResponseBody,
-			&expectedHttpStatusCode, &followRedirects, &verifySsl, &enableNotifications,
-			&notifyAfterNumberOfFailures, &continuouslySendNotifications, pq.Array(&notifiers),
-			&createdAt, &updatedAt); err != nil {
+		service, err := scanService(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, model.Service{
-			Id:                            id,
-			Name:                          name,
-			Type:                          serviceType,
-			IntervalInSeconds:             intervalInSeconds,
-			Endpoint:                      endpoint,
-			HttpMethod:                    httpMethod,
-			RequestTimeoutInSeconds:       requestTimeoutInSeconds,
-			HttpHeaders:                   httpHeaders,
-			HttpBody:                      httpBody,
-			ExpectedHttpResponseBody:      expectedHttpResponseBody,
-			ExpectedHttpStatusCode:        expectedHttpStatusCode,
-			FollowRedirects:               followRedirects,
-			VerifySsl:                     verifySsl,
-			EnableNotifications:           enableNotifications,
-			NotifyAfterNumberOfFailures:   notifyAfterNumberOfFailures,
-			ContinuouslySendNotifications: continuouslySendNotifications,
-			Notifiers:                     notifiers,
-			CreatedAt:                     createdAt,
-			UpdatedAt:                     updatedAt,
-		})
+		result = append(result, service)
 	}
 
 	return result, nil
@@ -214,51 +378,53 @@ func SelectServicesCount(ctx context.Context) (int, error) {
 
 func SelectServiceById(ctx context.Context, serviceId string) (model.Service, error) {
 	row := selectServiceByIdStatement.QueryRowContext(ctx, serviceId)
-
-	var id, name, endpoint, httpMethod, httpHeaders, httpBody, expectedHttpResponseBody string
-	var serviceType model.ServiceType
-	var intervalInSeconds, requestTimeoutInSeconds, expectedHttpStatusCode, notifyAfterNumberOfFailures int
-	var followRedirects, verifySsl, enableNotifications, continuouslySendNotifications bool
-	var notifiers []string
-	var createdAt, updatedAt time.Time
-
-	if err := row.Scan(&id, &name, &serviceType, &intervalInSeconds, &endpoint, &httpMethod,
-		&requestTimeoutInSeconds, &httpHeaders, &httpBody, &expectedHttpResponseBody,
-		&expectedHttpStatusCode, &followRedirects, &verifySsl, &enableNotifications,
-		&notifyAfterNumberOfFailures, &continuouslySendNotifications, pq.Array(&notifiers),
-		&createdAt, &updatedAt); err != nil {
-		return model.Service{}, err
-	}
-
-	return model.Service{
-		Id:                            id,
-		Name:                          name,
-		Type:                          serviceType,
-		IntervalInSeconds:             intervalInSeconds,
-		Endpoint:                      endpoint,
-		HttpMethod:                    httpMethod,
-		RequestTimeoutInSeconds:       requestTimeoutInSeconds,
-		HttpHeaders:                   httpHeaders,
-		HttpBody:                      httpBody,
-		ExpectedHttpResponseBody:      expectedHttpResponseBody,
-		ExpectedHttpStatusCode:        expectedHttpStatusCode,
-		FollowRedirects:               followRedirects,
-		VerifySsl:                     verifySsl,
-		EnableNotifications:           enableNotifications,
-		NotifyAfterNumberOfFailures:   notifyAfterNumberOfFailures,
-		ContinuouslySendNotifications: continuouslySendNotifications,
-		Notifiers:                     notifiers,
-		CreatedAt:                     createdAt,
-		UpdatedAt:                     updatedAt,
-	}, nil
+	return scanService(row)
 }
 
 func UpdateServiceById(ctx context.Context, serviceId string, service model.Service) error {
+	notificationTemplates, err := marshalNotificationTemplates(service.NotificationTemplates)
+	if err != nil {
+		return err
+	}
+
 	if _, err := updateServiceByIdStatement.ExecContext(ctx, serviceId, service.Name, service.Type, service.IntervalInSeconds,
 		service.Endpoint, service.HttpMethod, service.RequestTimeoutInSeconds, service.HttpHeaders, service.HttpBody,
 		service.ExpectedHttpResponseBody, service.ExpectedHttpStatusCode, service.FollowRedirects, service.VerifySsl,
 		service.EnableNotifications, service.NotifyAfterNumberOfFailures, service.ContinuouslySendNotifications,
-		pq.Array(service.Notifiers), time.Now()); err != nil {
+		pq.Array(service.Notifiers), pq.Array(service.Tags), pq.Array(service.ParentIds), service.EscalationPolicyId,
+		service.FlappingThreshold, service.FlappingWindow, notificationTemplates, service.SloTarget,
+		service.SloWindowInDays, service.SloFastBurnRate, service.SloSlowBurnRate, service.SloAlertsOnly,
+		service.LatencyThresholdInMs, service.LatencyPercentile, service.LatencyWindow, service.LatencyDeviation,
+		time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// marshalNotificationTemplates stores missing templates as an empty array.
+func marshalNotificationTemplates(templates []model.NotificationTemplate) ([]byte, error) {
+	if templates == nil {
+		templates = make([]model.NotificationTemplate, 0)
+	}
+	return json.Marshal(templates)
+}
+
+func UpdateServiceIsFlappingTx(ctx context.Context, tx *sql.Tx, serviceId string, isFlapping bool) error {
+	if _, err := tx.ExecContext(ctx, updateServiceIsFlappingQuery, serviceId, isFlapping); err != nil {
+		return err
+	}
+	return nil
+}
+
+func UpdateServiceSloBurnStateTx(ctx context.Context, tx *sql.Tx, serviceId string, state model.SloBurnState) error {
+	if _, err := tx.ExecContext(ctx, updateServiceSloBurnStateQuery, serviceId, state); err != nil {
+		return err
+	}
+	return nil
+}
+
+func UpdateServiceIsDegradedTx(ctx context.Context, tx *sql.Tx, serviceId string, isDegraded bool) error {
+	if _, err := tx.ExecContext(ctx, updateServiceIsDegradedQuery, serviceId, isDegraded); err != nil {
 		return err
 	}
 	return nil
@@ -270,3 +436,87 @@ func DeleteServiceById(ctx context.Context, serviceId string) error {
 	}
 	return nil
 }
+
+func UpdateServiceEnabledById(ctx context.Context, serviceId string, enabled bool) error {
+	result, err := updateServiceEnabledByIdStatement.ExecContext(ctx, serviceId, enabled, time.Now())
+	if err != nil {
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func UpdateServicesEnabledByIds(ctx context.Context, serviceIds []string, enabled bool) (int64, error) {
+	result, err := updateServicesEnabledByIdsStatement.ExecContext(ctx, pq.Array(serviceIds), enabled, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func UpdateAllServicesEnabled(ctx context.Context, enabled bool) (int64, error) {
+	result, err := updateAllServicesEnabledStatement.ExecContext(ctx, enabled, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func SelectServiceDependencies(ctx context.Context) ([]model.ServiceDependency, error) {
+	rows, err := selectServiceDependenciesStatement.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var id, name string
+	var parentIds []string
+
+	result := make([]model.ServiceDependency, 0)
+
+	for rows.Next() {
+		if err := rows.Scan(&id, &name, pq.Array(&parentIds)); err != nil {
+			return nil, err
+		}
+
+		result = append(result, model.ServiceDependency{
+			ServiceId: id,
+			Name:      name,
+			ParentIds: parentIds,
+		})
+	}
+
+	return result, nil
+}
+
+// RemoveNotifierId removes the notifier and its template overrides from all services.
+func RemoveNotifierId(ctx context.Context, notifierId string) error {
+	if _, err := removeNotifierIdStatement.ExecContext(ctx, notifierId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveEscalationPolicyId removes the escalation policy from all services that reference it.
+func RemoveEscalationPolicyId(ctx context.Context, policyId string) error {
+	if _, err := removeEscalationPolicyIdStatement.ExecContext(ctx, policyId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveParentId removes the service from the parents of all other services.
+func RemoveParentId(ctx context.Context, parentId string) error {
+	if _, err := removeParentIdStatement.ExecContext(ctx, parentId); err != nil {
+		return err
+	}
+	return nil
+}
@@ -35,8 +35,13 @@ func LoadConfig() error {
 	viper.SetDefault("USERS", "")
 
 	viper.SetDefault("SERVER_PORT", 8081)
+	viper.SetDefault("PUBLIC_URL", "")
 	viper.SetDefault("SCHEDULER_ENABLED", true)
 	viper.SetDefault("SCHEDULER_NUMBER_OF_WORKERS", 5)
+	viper.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 8)
+	viper.SetDefault("NOTIFICATION_RATE_LIMIT", 0)
+	viper.SetDefault("NOTIFICATION_DIGEST_INTERVAL_IN_SECONDS", 300)
+	viper.SetDefault("EXEC_NOTIFIER_ENABLED", false)
 
 	viper.AutomaticEnv()
 
@@ -63,6 +63,7 @@ func LoadDatabase(host string, port int, user, password, databaseName, migration
 
 	repository.SetDatabase(database)
 	go StartScheduleJob(GetConfig().SchedulerEnabled)
+	go StartNotificationDelivery(GetConfig().SchedulerEnabled)
 
 	return nil
 }
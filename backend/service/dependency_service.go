@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+)
+
+var (
+	ErrUnknownParentService = errors.New("parent service does not exist")
+	ErrDependencyCycle      = errors.New("service dependencies must not contain a cycle")
+)
+
+func GetServiceDependencies(ctx context.Context) ([]model.ServiceDependency, error) {
+	return repository.SelectServiceDependencies(ctx)
+}
+
+// validateServiceDependencies checks that all parents of the service exist and that the new parents do not
+// introduce a cycle into the dependency graph.
+func validateServiceDependencies(ctx context.Context, service model.Service) error {
+	if len(service.ParentIds) == 0 {
+		return nil
+	}
+
+	dependencies, err := repository.SelectServiceDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	parentsByServiceId := make(map[string][]string, len(dependencies)+1)
+	for _, dependency := range dependencies {
+		parentsByServiceId[dependency.ServiceId] = dependency.ParentIds
+	}
+
+	for _, parentId := range service.ParentIds {
+		if parentId == service.Id {
+			return ErrDependencyCycle
+		}
+
+		if _, exists := parentsByServiceId[parentId]; !exists {
+			return fmt.Errorf("%w: '%s'", ErrUnknownParentService, parentId)
+		}
+	}
+
+	parentsByServiceId[service.Id] = service.ParentIds
+
+	if hasDependencyCycle(parentsByServiceId, service.Id, make(map[string]bool), make(map[string]bool)) {
+		return ErrDependencyCycle
+	}
+	return nil
+}
+
+func hasDependencyCycle(parentsByServiceId map[string][]string, serviceId string, visited, onPath map[string]bool) bool {
+	if onPath[serviceId] {
+		return true
+	}
+
+	if visited[serviceId] {
+		return false
+	}
+
+	visited[serviceId] = true
+	onPath[serviceId] = true
+
+	for _, parentId := range parentsByServiceId[serviceId] {
+		if hasDependencyCycle(parentsByServiceId, parentId, visited, onPath) {
+			return true
+		}
+	}
+
+	onPath[serviceId] = false
+	return false
+}
+
+// findDownParent returns the first enabled parent of the service whose latest check failed or nil.
+func findDownParent(ctx context.Context, service model.Service) (*model.Service, error) {
+	for _, parentId := range service.ParentIds {
+		parent, err := repository.SelectServiceById(ctx, parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !parent.Enabled {
+			continue
+		}
+
+		isOnline, err := repository.SelectIsOnline(ctx, parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !isOnline {
+			return &parent, nil
+		}
+	}
+	return nil, nil
+}
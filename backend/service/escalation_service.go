@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	escalationBatchSize = 100
+)
+
+var (
+	ErrUnknownEscalationPolicy = errors.New("escalation policy does not exist")
+	ErrUnknownNotifier         = errors.New("notifier does not exist")
+)
+
+func CreateEscalationPolicy(ctx context.Context, policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	if err := validateEscalationPolicy(policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	if err := repository.InsertEscalationPolicy(ctx, policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	return repository.SelectEscalationPolicyById(ctx, policy.Id)
+}
+
+func GetEscalationPolicies(ctx context.Context) ([]model.EscalationPolicy, error) {
+	return repository.SelectEscalationPolicies(ctx)
+}
+
+func GetEscalationPolicyById(ctx context.Context, id string) (model.EscalationPolicy, error) {
+	return repository.SelectEscalationPolicyById(ctx, id)
+}
+
+func UpdateEscalationPolicyById(ctx context.Context, id string, policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	if err := validateEscalationPolicy(policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	if err := repository.UpdateEscalationPolicyById(ctx, id, policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	return repository.SelectEscalationPolicyById(ctx, id)
+}
+
+func DeleteEscalationPolicyById(ctx context.Context, id string) error {
+	if err := repository.RemoveEscalationPolicyId(ctx, id); err != nil {
+		return err
+	}
+	return repository.DeleteEscalationPolicyById(ctx, id)
+}
+
+func validateEscalationPolicy(policy model.EscalationPolicy) error {
+	for _, step := range policy.Steps {
+		for _, notifierId := range step.NotifierIds {
+			if _, err := notificationSystem.GetNotifierById(notifierId); err != nil {
+				return fmt.Errorf("%w: '%s'", ErrUnknownNotifier, notifierId)
+			}
+		}
+	}
+	return nil
+}
+
+// validateServiceEscalationPolicy checks that the escalation policy the service references exists.
+func validateServiceEscalationPolicy(ctx context.Context, service model.Service) error {
+	if len(service.EscalationPolicyId) == 0 {
+		return nil
+	}
+
+	if _, err := repository.SelectEscalationPolicyById(ctx, service.EscalationPolicyId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: '%s'", ErrUnknownEscalationPolicy, service.EscalationPolicyId)
+		}
+		return err
+	}
+	return nil
+}
+
+// startEscalation notifies the first step of the escalation policy of the service and schedules the next step.
+// It returns false if the service has no escalation policy.
+func startEscalation(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, incident *model.Incident,
+	details model.NotificationDetails) (bool, error) {
+	if len(service.EscalationPolicyId) == 0 {
+		return false, nil
+	}
+
+	policy, err := repository.SelectEscalationPolicyByIdTx(ctx, tx, service.EscalationPolicyId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warnf("Escalation policy '%s' of service '%s' not found", service.EscalationPolicyId, service.Name)
+			return false, nil
+		}
+		return false, err
+	}
+
+	logger.Infof("Escalating incident of service '%s' with policy '%s'", service.Name, policy.Name)
+	incident.EscalationPolicyId = policy.Id
+	incident.EscalationStep = 0
+	incident.NextEscalationAt = policy.NextStepAt(0, time.Now())
+	if err := repository.UpdateIncidentTx(ctx, tx, *incident); err != nil {
+		return false, err
+	}
+
+	return true, queueNotifications(ctx, tx, logger, service, policy.NotifierIds(0), false, details)
+}
+
+// escalatedNotifierIds returns the notifiers of all escalation steps the incident has reached. The notifiers of the
+// service are returned if the policy was deleted.
+func escalatedNotifierIds(ctx context.Context, tx *sql.Tx, service model.Service, incident model.Incident) ([]string, error) {
+	policy, err := repository.SelectEscalationPolicyByIdTx(ctx, tx, incident.EscalationPolicyId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Notifiers, nil
+		}
+		return nil, err
+	}
+	return policy.NotifierIds(incident.EscalationStep), nil
+}
+
+func escalateDueIncidents() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ids, err := repository.SelectDueIncidentIds(ctx, escalationBatchSize)
+	cancel()
+	if err != nil {
+		log.Errorf("Unable to get due escalations: '%s'", err)
+		return
+	}
+
+	for _, id := range ids {
+		escalate(id)
+	}
+}
+
+// escalate queues the notifications of the next escalation step of the incident.
+func escalate(incidentId string) {
+	logger := log.WithFields(log.Fields{"incidentId": incidentId})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := repository.BeginnTransaction()
+	if err != nil {
+		logger.Errorf("Unable to start transaction: '%s'", err)
+		return
+	}
+
+	if err := escalateTx(ctx, tx, logger, incidentId); err != nil {
+		logger.Errorf("Unable to escalate: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Errorf("Error commiting transaction: '%s'", err)
+	}
+}
+
+func escalateTx(ctx context.Context, tx *sql.Tx, logger *log.Entry, incidentId string) error {
+	incident, err := repository.SelectIncidentByIdLockedTx(ctx, tx, incidentId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	if incident.ResolvedAt != nil || incident.IsAcknowledged() ||
+		incident.NextEscalationAt == nil || incident.NextEscalationAt.After(now) {
+		return nil
+	}
+
+	service, err := repository.SelectServiceById(ctx, incident.ServiceId)
+	if err != nil {
+		return err
+	}
+
+	policy, err := repository.SelectEscalationPolicyByIdTx(ctx, tx, incident.EscalationPolicyId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		logger.Infof("Escalation policy '%s' was deleted. Stopping escalation", incident.EscalationPolicyId)
+		incident.NextEscalationAt = nil
+		return repository.UpdateIncidentTx(ctx, tx, incident)
+	}
+
+	step := incident.EscalationStep + 1
+	if step >= len(policy.Steps) {
+		incident.NextEscalationAt = nil
+		return repository.UpdateIncidentTx(ctx, tx, incident)
+	}
+
+	logger.Infof("Escalating incident of service '%s' to step %d of policy '%s'", service.Name, step+1, policy.Name)
+	details := model.NotificationDetails{
+		Reason:              incident.Reason,
+		ConsecutiveFailures: incident.FailureCount,
+		DownSince:           &incident.CreatedAt,
+	}
+	if err := queueNotifications(ctx, tx, logger, service, policy.Steps[step].NotifierIds, false, details); err != nil {
+		return err
+	}
+
+	incident.EscalationStep = step
+	incident.NextEscalationAt = policy.NextStepAt(step, now)
+	return repository.UpdateIncidentTx(ctx, tx, incident)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultFlappingWindow = 20
+)
+
+// updateFlapping detects if the service changes its state more than FlappingThreshold times within the last
+// FlappingWindow checks. The flapping state is stopped once the number of state changes drops to half of the
+// threshold. A single notification is queued when the service starts or stops flapping. It returns true if the
+// individual up and down notifications of the check must be suppressed.
+func updateFlapping(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, check *model.Check,
+	failure *model.Failure) (bool, error) {
+	if check == nil || check.IsMaintenance || check.IsDependencyDown {
+		return service.IsFlapping, nil
+	}
+
+	if service.FlappingThreshold == 0 {
+		if service.IsFlapping {
+			logger.Infof("Flapping detection of service '%s' is disabled. Resetting flapping state", service.Name)
+			return false, repository.UpdateServiceIsFlappingTx(ctx, tx, service.Id, false)
+		}
+		return false, nil
+	}
+
+	window := service.FlappingWindow
+	if window == 0 {
+		window = defaultFlappingWindow
+	}
+
+	lastChecks, err := repository.GetLastNChecksTx(ctx, tx, service.Id, window-1)
+	if err != nil {
+		return false, err
+	}
+
+	checks := append([]model.Check{*check}, lastChecks...)
+	stateChanges := model.CountStateChanges(checks)
+
+	isFlapping := service.IsFlapping
+	if !isFlapping && stateChanges > service.FlappingThreshold {
+		isFlapping = true
+	} else if isFlapping && stateChanges <= service.FlappingThreshold/2 {
+		isFlapping = false
+	}
+
+	if isFlapping == service.IsFlapping {
+		return isFlapping, nil
+	}
+
+	if err := repository.UpdateServiceIsFlappingTx(ctx, tx, service.Id, isFlapping); err != nil {
+		return false, err
+	}
+
+	if isFlapping {
+		logger.Infof("Service '%s' started flapping with %d state changes in the last %d checks", service.Name,
+			stateChanges, len(checks))
+		reason := fmt.Sprintf("Service is flapping: %d state changes in the last %d checks", stateChanges, len(checks))
+		return true, queueServiceNotifications(ctx, tx, logger, service, false, model.NotificationDetails{Reason: reason})
+	}
+
+	logger.Infof("Service '%s' stopped flapping", service.Name)
+	if failure != nil {
+		reason := fmt.Sprintf("Service stopped flapping and is down: %s", failure.Reason)
+		return true, queueServiceNotifications(ctx, tx, logger, service, false, model.NotificationDetails{Reason: reason})
+	}
+	return true, queueServiceNotifications(ctx, tx, logger, service, true, model.NotificationDetails{Reason: "Service stopped flapping"})
+}
+
+func GetIsFlapping(ctx context.Context, serviceId string) (bool, error) {
+	service, err := repository.SelectServiceById(ctx, serviceId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return service.IsFlapping, err
+}
@@ -182,6 +182,9 @@ func csvRowToService(row []string) (model.Service, error) {
 		NotifyAfterNumberOfFailures:   notifyAfterNumberOfFailuresInt,
 		ContinuouslySendNotifications: continuouslySendNotificationsBool,
 		Notifiers:                     notifiersSlice,
+		Tags:                          make([]string, 0),
+		Enabled:                       true,
+		ParentIds:                     make([]string, 0),
 		CreatedAt:                     time.Now(),
 		UpdatedAt:                     time.Now(),
 	}, nil
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	ErrNoOpenIncident   = errors.New("service has no open incident")
+	ErrIncidentResolved = errors.New("incident is already resolved")
+)
+
+// openIncident returns the open incident of the service. A new incident is only opened once the failures reach
+// NotifyAfterNumberOfFailures, the same number that triggers the down notification, so that single failed checks do
+// not count as incidents. It starts with the first failed check of the outage and the failures stored so far are
+// assigned to it. It returns nil if the threshold is not reached yet.
+func openIncident(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, reason string) (*model.Incident, error) {
+	incident, err := repository.SelectOpenIncidentByServiceIdTx(ctx, tx, service.Id)
+	if err == nil {
+		return &incident, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	checks, err := repository.GetLastNChecksTx(ctx, tx, service.Id, service.NotifyAfterNumberOfFailures)
+	if err != nil {
+		return nil, err
+	}
+	if countFailures(checks)+1 < service.NotifyAfterNumberOfFailures {
+		return nil, nil
+	}
+
+	logger.Infof("Opening incident for service '%s'", service.Name)
+	incident = model.NewIncident(service.Id, reason)
+	for _, check := range checks {
+		if !check.IsFailure {
+			break
+		}
+		incident.CreatedAt = check.CreatedAt
+	}
+
+	if err := repository.InsertIncidentTx(ctx, tx, incident); err != nil {
+		return nil, err
+	}
+
+	incident.FailureCount, err = repository.UpdateFailuresIncidentIdTx(ctx, tx, service.Id, incident.Id, incident.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// notifyServiceDown queues the down notification of the incident. Services with an escalation policy notify the
+// first step of the policy, repeated notifications go to all steps reached so far. Acknowledged incidents are not
+// notified again.
+func notifyServiceDown(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, incident model.Incident,
+	details model.NotificationDetails) error {
+	if incident.IsAcknowledged() {
+		logger.Infof("Incident of service '%s' is acknowledged. Not sending notification", service.Name)
+		return nil
+	}
+
+	if incident.IsEscalated() {
+		notifierIds, err := escalatedNotifierIds(ctx, tx, service, incident)
+		if err != nil {
+			return err
+		}
+		return queueNotifications(ctx, tx, logger, service, notifierIds, false, details)
+	}
+
+	escalated, err := startEscalation(ctx, tx, logger, service, &incident, details)
+	if err != nil || escalated {
+		return err
+	}
+	return queueNotifications(ctx, tx, logger, service, service.Notifiers, false, details)
+}
+
+// resolveIncident resolves the open incident of the service. It returns nil if the service has no open incident.
+func resolveIncident(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service) (*model.Incident, error) {
+	incident, err := repository.SelectOpenIncidentByServiceIdTx(ctx, tx, service.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	logger.Infof("Resolving incident of service '%s'", service.Name)
+	now := time.Now()
+	incident.NextEscalationAt = nil
+	incident.ResolvedAt = &now
+	if err := repository.UpdateIncidentTx(ctx, tx, incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// recoveryNotifierIds returns the notifiers the up notification of the incident is sent with.
+func recoveryNotifierIds(ctx context.Context, tx *sql.Tx, service model.Service, incident model.Incident) ([]string, error) {
+	if incident.IsEscalated() {
+		return escalatedNotifierIds(ctx, tx, service, incident)
+	}
+	return service.Notifiers, nil
+}
+
+func GetIncidents(ctx context.Context, serviceId, status string, pageSize, page int) ([]model.Incident, error) {
+	return repository.SelectIncidents(ctx, serviceId, status, pageSize, pageSize*page)
+}
+
+func GetIncidentsCount(ctx context.Context, serviceId, status string) (int, error) {
+	return repository.SelectIncidentsCount(ctx, serviceId, status)
+}
+
+func GetIncidentById(ctx context.Context, id string) (model.Incident, error) {
+	incident, err := repository.SelectIncidentById(ctx, id)
+	if err != nil {
+		return model.Incident{}, err
+	}
+
+	notes, err := repository.SelectIncidentNotes(ctx, incident.Id)
+	if err != nil {
+		return model.Incident{}, err
+	}
+
+	incident.Notes = notes
+	return incident, nil
+}
+
+func GetIncidentStatistics(ctx context.Context, serviceId string, from, to time.Time) (model.IncidentStatistics, error) {
+	return repository.SelectIncidentStatistics(ctx, serviceId, from, to)
+}
+
+// AcknowledgeIncident marks the incident as being worked on. Repeated down notifications and further escalation
+// steps are suppressed until the service is up again.
+func AcknowledgeIncident(ctx context.Context, id, user string) (model.Incident, error) {
+	return acknowledgeIncident(ctx, user, func(tx *sql.Tx) (model.Incident, error) {
+		return repository.SelectIncidentByIdForUpdateTx(ctx, tx, id)
+	})
+}
+
+// AcknowledgeServiceIncident acknowledges the open incident of the service.
+func AcknowledgeServiceIncident(ctx context.Context, serviceId, user string) (model.Incident, error) {
+	return acknowledgeIncident(ctx, user, func(tx *sql.Tx) (model.Incident, error) {
+		incident, err := repository.SelectOpenIncidentByServiceIdTx(ctx, tx, serviceId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Incident{}, ErrNoOpenIncident
+		}
+		return incident, err
+	})
+}
+
+func acknowledgeIncident(ctx context.Context, user string, selectIncident func(tx *sql.Tx) (model.Incident, error)) (model.Incident, error) {
+	tx, err := repository.BeginnTransaction()
+	if err != nil {
+		return model.Incident{}, err
+	}
+
+	incident, err := selectIncident(tx)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return model.Incident{}, err
+	}
+
+	if incident.ResolvedAt != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return model.Incident{}, ErrIncidentResolved
+	}
+
+	if !incident.IsAcknowledged() {
+		now := time.Now()
+		incident.AcknowledgedAt = &now
+		incident.AcknowledgedBy = user
+		incident.NextEscalationAt = nil
+		if err := repository.UpdateIncidentTx(ctx, tx, incident); err != nil {
+			if err := tx.Rollback(); err != nil {
+				log.Errorf("Error rolling back transaction: '%s'", err)
+			}
+			return model.Incident{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Incident{}, err
+	}
+	return GetIncidentById(ctx, incident.Id)
+}
+
+func AddIncidentNote(ctx context.Context, note model.IncidentNote) (model.IncidentNote, error) {
+	if _, err := repository.SelectIncidentById(ctx, note.IncidentId); err != nil {
+		return model.IncidentNote{}, err
+	}
+
+	if err := repository.InsertIncidentNote(ctx, note); err != nil {
+		return model.IncidentNote{}, err
+	}
+	return note, nil
+}
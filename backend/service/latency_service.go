@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// updateLatencyDegradation aggregates the latency of the last LatencyWindow successful checks and compares it with
+// the fixed threshold and the baseline of the last week. A degraded notification is queued when one of them is
+// exceeded, an up notification with IsDegraded set once the latency is back to normal, so that it is not rendered as
+// the end of an outage. Failed checks do not change the state, the
+// service is down then. The check must already be stored.
+func updateLatencyDegradation(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service,
+	check *model.Check) error {
+	if !service.LatencyAlertsEnabled() {
+		if service.IsDegraded {
+			logger.Infof("Latency alerts of service '%s' are disabled. Resetting degraded state", service.Name)
+			return repository.UpdateServiceIsDegradedTx(ctx, tx, service.Id, false)
+		}
+		return nil
+	}
+
+	if check == nil || check.IsFailure || check.IsMaintenance {
+		return nil
+	}
+
+	latencies, oldest, err := repository.SelectLastLatenciesTx(ctx, tx, service.Id, service.LatencyCheckWindow())
+	if err != nil {
+		return err
+	}
+	if len(latencies) < service.LatencyCheckWindow() {
+		return nil
+	}
+
+	latency := model.AggregateLatency(latencies, service.LatencyPercentile)
+
+	violations := make([]string, 0, 2)
+	if service.LatencyThresholdInMs > 0 && latency > float64(service.LatencyThresholdInMs) {
+		violations = append(violations, fmt.Sprintf("above the threshold of %dms", service.LatencyThresholdInMs))
+	}
+
+	if service.LatencyDeviation > 0 {
+		baseline, err := repository.SelectLatencyBaselineTx(ctx, tx, service.Id, oldest.Add(-model.LatencyBaselinePeriod), oldest)
+		if err != nil {
+			return err
+		}
+		if limit, ok := baseline.Limit(service.LatencyDeviation); ok && latency > limit {
+			violations = append(violations, fmt.Sprintf("above the baseline of %.0fms (%.0fms average of the last week + %g standard deviations)",
+				limit, baseline.Mean, service.LatencyDeviation))
+		}
+	}
+
+	isDegraded := len(violations) > 0
+	if isDegraded == service.IsDegraded {
+		return nil
+	}
+
+	if err := repository.UpdateServiceIsDegradedTx(ctx, tx, service.Id, isDegraded); err != nil {
+		return err
+	}
+
+	details := model.NotificationDetails{IsDegraded: true, LatencyInMs: int64(latency)}
+	if isDegraded {
+		logger.Infof("Latency of service '%s' is degraded with %.0fms", service.Name, latency)
+		details.Reason = fmt.Sprintf("Latency is degraded: %s of the last %d checks is %.0fms, %s", service.LatencyAggregationName(),
+			len(latencies), latency, strings.Join(violations, " and "))
+		return queueServiceNotifications(ctx, tx, logger, service, false, details)
+	}
+
+	logger.Infof("Latency of service '%s' is back to normal", service.Name)
+	details.Reason = fmt.Sprintf("Latency is back to normal: %s of the last %d checks is %.0fms", service.LatencyAggregationName(),
+		len(latencies), latency)
+	return queueServiceNotifications(ctx, tx, logger, service, true, details)
+}
+
+func GetIsDegraded(ctx context.Context, serviceId string) (bool, error) {
+	service, err := repository.SelectServiceById(ctx, serviceId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return service.IsDegraded, err
+}
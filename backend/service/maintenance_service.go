@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	"time"
+)
+
+var (
+	ErrInvalidMaintenanceDuration = errors.New("a recurring maintenance window must be shorter than its recurrence period")
+)
+
+func CreateMaintenance(ctx context.Context, maintenance model.Maintenance) (model.Maintenance, error) {
+	if err := validateMaintenance(maintenance); err != nil {
+		return model.Maintenance{}, err
+	}
+
+	if err := repository.InsertMaintenance(ctx, maintenance); err != nil {
+		return model.Maintenance{}, err
+	}
+
+	return repository.SelectMaintenanceById(ctx, maintenance.Id)
+}
+
+func GetMaintenances(ctx context.Context) ([]model.Maintenance, error) {
+	return repository.SelectMaintenances(ctx)
+}
+
+func GetMaintenanceById(ctx context.Context, id string) (model.Maintenance, error) {
+	return repository.SelectMaintenanceById(ctx, id)
+}
+
+func UpdateMaintenanceById(ctx context.Context, id string, maintenance model.Maintenance) (model.Maintenance, error) {
+	if err := validateMaintenance(maintenance); err != nil {
+		return model.Maintenance{}, err
+	}
+
+	if err := repository.UpdateMaintenanceById(ctx, id, maintenance); err != nil {
+		return model.Maintenance{}, err
+	}
+
+	return repository.SelectMaintenanceById(ctx, id)
+}
+
+func DeleteMaintenanceById(ctx context.Context, id string) error {
+	return repository.DeleteMaintenanceById(ctx, id)
+}
+
+func validateMaintenance(maintenance model.Maintenance) error {
+	period := maintenance.Period()
+	if period > 0 && maintenance.EndAt.Sub(maintenance.StartAt) >= period {
+		return ErrInvalidMaintenanceDuration
+	}
+	return nil
+}
+
+// getActiveMaintenance returns the maintenance window the service is currently in or nil.
+// Windows that skip checks take precedence over windows that only flag them.
+func getActiveMaintenance(ctx context.Context, service model.Service) (*model.Maintenance, error) {
+	now := time.Now()
+
+	maintenances, err := repository.SelectStartedMaintenancesForService(ctx, service.Id, service.Tags, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var active *model.Maintenance
+	for i, maintenance := range maintenances {
+		if !maintenance.IsActiveAt(now) || !maintenance.AppliesTo(service) {
+			continue
+		}
+
+		if active == nil || (maintenance.SkipChecks && !active.SkipChecks) {
+			active = &maintenances[i]
+		}
+	}
+	return active, nil
+}
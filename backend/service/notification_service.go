@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/notifier"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	notificationDeliveryInterval  = 5 * time.Second
+	notificationDeliveryBatchSize = 100
+	notificationRateLimitWindow   = time.Minute
+)
+
+// queueNotifications stores a notification of the service for every recipient of the notifiers. They are delivered
+// by StartNotificationDelivery once the transaction is committed.
+func queueNotifications(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, notifierIds []string,
+	isUpNotification bool, details model.NotificationDetails) error {
+	for _, recipient := range notificationSystem.GetRecipients(notifierIds) {
+		notification := model.NewNotification(service, recipient.GetId(), isUpNotification, details)
+
+		payload, err := notifier.RenderNotification(recipient, service, isUpNotification, details, notification.CreatedAt)
+		if err != nil {
+			logger.Errorf("Unable to render notification for notifier '%s' - '%s'", recipient.GetId(), err)
+			notification.Status = model.NotificationStatusFailed
+			notification.LastError = err.Error()
+		}
+		notification.Payload = payload
+
+		if err := repository.SkipPendingNotificationsTx(ctx, tx, notification); err != nil {
+			return err
+		}
+
+		logger.Infof("Queueing notification for service '%s' with notifier '%s'", service.Name, recipient.GetId())
+		if err := repository.InsertNotificationTx(ctx, tx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueServiceNotifications notifies the notifiers of the service, or the first step of its escalation policy. It is
+// used for notifications that do not belong to an incident, e.g. when a service starts flapping.
+func queueServiceNotifications(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service,
+	isUpNotification bool, details model.NotificationDetails) error {
+	if !service.EnableNotifications {
+		return nil
+	}
+
+	notifierIds := service.Notifiers
+	if len(service.EscalationPolicyId) > 0 {
+		policy, err := repository.SelectEscalationPolicyByIdTx(ctx, tx, service.EscalationPolicyId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil {
+			notifierIds = policy.NotifierIds(0)
+		}
+	}
+
+	return queueNotifications(ctx, tx, logger, service, notifierIds, isUpNotification, details)
+}
+
+func StartNotificationDelivery(enabled bool) {
+	if !enabled {
+		log.Info("Notification delivery is disabled")
+		return
+	}
+	log.Info("Starting notification delivery")
+
+	ticker := time.NewTicker(notificationDeliveryInterval)
+	for range ticker.C {
+		escalateDueIncidents()
+		queueDueDigests()
+		queueQuietHourSummaries()
+		deliverDueNotifications()
+	}
+}
+
+func deliverDueNotifications() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ids, err := repository.SelectDueNotificationIds(ctx, notificationDeliveryBatchSize)
+	cancel()
+	if err != nil {
+		log.Errorf("Unable to get due notifications: '%s'", err)
+		return
+	}
+
+	for _, id := range ids {
+		DeliverNotification(id)
+	}
+}
+
+// DeliverNotification sends the notification and records the attempt. Failed deliveries are retried with an
+// exponential backoff until NOTIFICATION_MAX_ATTEMPTS is reached.
+func DeliverNotification(id string) {
+	logger := log.WithFields(log.Fields{"notificationId": id})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tx, err := repository.BeginnTransaction()
+	if err != nil {
+		logger.Errorf("Unable to start transaction: '%s'", err)
+		return
+	}
+
+	notification, err := repository.SelectNotificationByIdLockedTx(ctx, tx, id)
+	if err != nil || notification.Status != model.NotificationStatusPending {
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			logger.Errorf("Unable to lock notification: '%s'", err)
+		}
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	// notifications of a service are delivered in order, e.g. an up notification waits for a down notification
+	// that is still retried
+	var predecessorAttemptAt *time.Time
+	if !notification.IsDigest {
+		predecessorAttemptAt, err = repository.SelectPendingPredecessorNextAttemptAtTx(ctx, tx, notification)
+	}
+	if err != nil {
+		logger.Errorf("Unable to check older notifications: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	isQuiet := false
+	if predecessorAttemptAt == nil {
+		isQuiet, err = applyQuietHours(ctx, tx, logger, &notification)
+	}
+	if err != nil {
+		logger.Errorf("Unable to check quiet hours: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	rateLimited := false
+	if predecessorAttemptAt == nil && !isQuiet {
+		rateLimited, err = isRateLimited(ctx, tx, notification)
+	}
+	if err != nil {
+		logger.Errorf("Unable to check rate limit: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if predecessorAttemptAt != nil {
+		logger.Infof("An older notification with notifier '%s' is pending. Postponing notification", notification.NotifierId)
+		notification.NextAttemptAt = predecessorAttemptAt.Add(notificationDeliveryInterval)
+	} else if isQuiet {
+		logger.Infof("Notifier '%s' is in quiet hours. Notification is %s", notification.NotifierId,
+			strings.ToLower(string(notification.Status)))
+	} else if rateLimited {
+		logger.Infof("Rate limit of notifier '%s' exceeded. Coalescing notification into digest", notification.NotifierId)
+		notification.Status = model.NotificationStatusCoalesced
+	} else {
+		now := time.Now()
+		notification.Attempts++
+
+		output, err := sendQueuedNotification(ctx, notification)
+		notification.Output = output
+		if err != nil {
+			logger.Warnf("Delivery attempt %d with notifier '%s' failed: '%s'", notification.Attempts, notification.NotifierId, err)
+			notification.LastError = err.Error()
+
+			if notification.Attempts >= GetConfig().NotificationMaxAttempts {
+				notification.Status = model.NotificationStatusFailed
+			} else {
+				notification.NextAttemptAt = now.Add(notifier.RetryDelay(notification.Attempts))
+			}
+		} else {
+			logger.Infof("Delivered notification with notifier '%s'", notification.NotifierId)
+			notification.Status = model.NotificationStatusSent
+			notification.LastError = ""
+			notification.SentAt = &now
+		}
+	}
+
+	if err := repository.UpdateNotificationDeliveryTx(ctx, tx, notification); err != nil {
+		logger.Errorf("Unable to update notification: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Errorf("Error commiting transaction: '%s'", err)
+	}
+}
+
+func sendQueuedNotification(ctx context.Context, notification model.Notification) (string, error) {
+	recipient, err := notificationSystem.GetNotifierById(notification.NotifierId)
+	if err != nil {
+		return "", err
+	}
+
+	if notification.IsDigest {
+		return notifier.SendEventWithOutput(recipient, model.NotificationEvent{
+			NotificationId: notification.Id,
+			Service:        model.Service{Name: notification.ServiceName},
+			IsDigest:       true,
+			Message:        notification.Payload,
+			Date:           notification.CreatedAt,
+		})
+	}
+
+	service, err := repository.SelectServiceById(ctx, notification.ServiceId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("unable to get service: %w", err)
+		}
+		// the service was deleted after the notification was queued
+		service = model.Service{Id: notification.ServiceId, Name: notification.ServiceName}
+	}
+
+	return notifier.SendEventWithOutput(recipient, model.NotificationEvent{
+		NotificationId:   notification.Id,
+		Service:          service,
+		IsUpNotification: notification.IsUpNotification,
+		IsDegraded:       notification.IsDegradedNotification,
+		Failure:          model.Failure{ServiceId: notification.ServiceId, Reason: notification.Reason},
+		Details:          notification.Details,
+		Message:          notification.Payload,
+		Link:             notifier.ServiceLink(notification.ServiceId),
+		Date:             notification.CreatedAt,
+	})
+}
+
+// isRateLimited returns true if sending the notification would exceed the rate limit of its notifier or
+// NOTIFICATION_RATE_LIMIT. Digests and notifiers that do not support digests are never rate limited.
+func isRateLimited(ctx context.Context, tx *sql.Tx, notification model.Notification) (bool, error) {
+	if notification.IsDigest {
+		return false, nil
+	}
+
+	recipient, err := notificationSystem.GetNotifierById(notification.NotifierId)
+	if err != nil {
+		return false, nil
+	}
+
+	if _, ok := recipient.(model.DigestNotifier); !ok {
+		return false, nil
+	}
+
+	since := time.Now().Add(-notificationRateLimitWindow)
+
+	if rateLimit := recipient.GetRateLimit(); rateLimit > 0 {
+		count, err := repository.SelectSentNotificationsCountTx(ctx, tx, recipient.GetId(), since)
+		if err != nil {
+			return false, err
+		}
+		if count >= rateLimit {
+			return true, nil
+		}
+	}
+
+	if rateLimit := GetConfig().NotificationRateLimit; rateLimit > 0 {
+		count, err := repository.SelectSentNotificationsCountTx(ctx, tx, "", since)
+		if err != nil {
+			return false, err
+		}
+		return count >= rateLimit, nil
+	}
+	return false, nil
+}
+
+func queueDueDigests() {
+	interval := time.Duration(GetConfig().NotificationDigestIntervalInSeconds) * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	notifierIds, err := repository.SelectDueDigestNotifierIds(ctx, time.Now().Add(-interval))
+	cancel()
+	if err != nil {
+		log.Errorf("Unable to get due digests: '%s'", err)
+		return
+	}
+
+	for _, notifierId := range notifierIds {
+		queueDigest(notifierId, model.NotificationStatusCoalesced)
+	}
+}
+
+// queueDigest combines all coalesced or held notifications of the notifier into one digest notification.
+func queueDigest(notifierId string, status model.NotificationStatus) {
+	logger := log.WithFields(log.Fields{"notifierId": notifierId})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := repository.BeginnTransaction()
+	if err != nil {
+		logger.Errorf("Unable to start transaction: '%s'", err)
+		return
+	}
+
+	if err := queueDigestTx(ctx, tx, logger, notifierId, status); err != nil {
+		logger.Errorf("Unable to queue digest: '%s'", err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Errorf("Error commiting transaction: '%s'", err)
+	}
+}
+
+func queueDigestTx(ctx context.Context, tx *sql.Tx, logger *log.Entry, notifierId string, status model.NotificationStatus) error {
+	notifications, err := repository.SelectUndigestedNotificationsLockedTx(ctx, tx, notifierId, status)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+
+	ids := make([]string, 0, len(notifications))
+	items := make([]notifier.DigestItem, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.Id)
+		items = append(items, notifier.DigestItem{
+			ServiceId:        notification.ServiceId,
+			ServiceName:      notification.ServiceName,
+			IsUpNotification: notification.IsUpNotification,
+			IsDegraded:       notification.IsDegradedNotification,
+			Reason:           notification.Reason,
+			Date:             notification.CreatedAt,
+		})
+	}
+
+	digest := model.NewDigestNotification(notifierId)
+
+	payload, err := renderDigest(notifierId, notifier.NewDigestData(items, digest.CreatedAt))
+	if err != nil {
+		logger.Errorf("Unable to render digest - '%s'", err)
+		digest.Status = model.NotificationStatusFailed
+		digest.LastError = err.Error()
+	}
+	digest.Payload = payload
+
+	logger.Infof("Queueing digest of %d notifications", len(notifications))
+	if err := repository.InsertNotificationTx(ctx, tx, digest); err != nil {
+		return err
+	}
+	return repository.UpdateNotificationDigestIdTx(ctx, tx, ids, digest.Id)
+}
+
+func renderDigest(notifierId string, data model.TemplateData) (string, error) {
+	recipient, err := notificationSystem.GetNotifierById(notifierId)
+	if err != nil {
+		return "", err
+	}
+	return notifier.RenderDigest(recipient, data)
+}
+
+func GetNotifications(ctx context.Context, serviceId, notifierId string, status model.NotificationStatus, pageSize, page int) ([]model.Notification, error) {
+	return repository.SelectNotifications(ctx, serviceId, notifierId, status, pageSize, pageSize*page)
+}
+
+func GetNotificationsCount(ctx context.Context, serviceId, notifierId string, status model.NotificationStatus) (int, error) {
+	return repository.SelectNotificationsCount(ctx, serviceId, notifierId, status)
+}
@@ -1,105 +1,350 @@
 package service
 
 import (
-	"bytes"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/koloo91/monhttp/model"
 	"github.com/koloo91/monhttp/notifier"
+	"github.com/koloo91/monhttp/repository"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"strings"
-	"text/template"
 	"time"
 )
 
+// testNotifierServiceId is shared by the up and down test so that incident management notifiers resolve the
+// incident created by the down test.
+const testNotifierServiceId = "test"
+
+var (
+	ErrUnknownNotifierType         = errors.New("unknown notifier type")
+	ErrInvalidNotificationTemplate = errors.New("invalid notification template")
+	ErrInvalidNotifierConfig       = errors.New("invalid notifier configuration")
+)
+
 func GetNotifiers() []model.Notify {
 	return notificationSystem.GetNotifiers()
 }
 
-func UpdateNotifier(id string, body map[string]interface{}) error {
-	log.Infof("Updating notififier with id '%s'", id)
-	for k, v := range body {
-		viper.Set(fmt.Sprintf("NOTIFIER_%s_%s", strings.ToUpper(id), strings.ToUpper(k)), v)
+// GetNotifierTypes returns an unconfigured notifier of every registered type, their forms describe the configuration.
+func GetNotifierTypes() []model.Notify {
+	return notifier.TypeTemplates()
+}
+
+// CreateNotifier stores a new notifier. If the form values are invalid, ErrInvalidNotifierConfig is returned with an
+// error per field.
+func CreateNotifier(ctx context.Context, instance model.NotifierInstance) (model.Notify, []interface{}, error) {
+	log.Infof("Creating notifier of type '%s'", instance.Type)
+
+	if !notifier.HasType(instance.Type) {
+		return nil, nil, fmt.Errorf("%w: '%s'", ErrUnknownNotifierType, instance.Type)
+	}
+
+	notify, err := notifier.NewNotifier(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if validationErrors, err := validateNotifier(notify, instance); err != nil {
+		return nil, validationErrors, err
+	}
+
+	if err := validateNotificationSchedule(instance.Id, instance.Schedule); err != nil {
+		return nil, nil, err
 	}
 
-	notificationSystem.SetupDefaultNotifier()
+	if err := repository.InsertNotifier(ctx, instance); err != nil {
+		return nil, nil, err
+	}
+
+	if err := LoadNotifiers(ctx); err != nil {
+		return nil, nil, err
+	}
 
-	return viper.WriteConfig()
+	return notify, nil, nil
 }
 
-func TestNotifierUpTemplate(id string, body map[string]interface{}) error {
-	log.Infof("Test notififiers up template with id '%s'", id)
+func GetNotifierById(ctx context.Context, id string) (model.Notify, error) {
+	instance, err := repository.SelectNotifierById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifier.NewNotifier(instance)
+}
 
-	testNotify, err := setupTestNotifier(id, body)
+// UpdateNotifierById replaces the name and the configuration of the notifier. The type can not be changed. If the
+// form values are invalid, ErrInvalidNotifierConfig is returned with an error per field.
+func UpdateNotifierById(ctx context.Context, id string, instance model.NotifierInstance) (model.Notify, []interface{}, error) {
+	log.Infof("Updating notififier with id '%s'", id)
+
+	existing, err := repository.SelectNotifierById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instance.Id = id
+	instance.Type = existing.Type
+
+	notify, err := notifier.NewNotifier(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if validationErrors, err := validateNotifier(notify, instance); err != nil {
+		return nil, validationErrors, err
+	}
+
+	if err := validateNotificationSchedule(id, instance.Schedule); err != nil {
+		return nil, nil, err
+	}
+
+	if err := repository.UpdateNotifierById(ctx, id, instance); err != nil {
+		return nil, nil, err
+	}
+
+	if err := LoadNotifiers(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return notify, nil, nil
+}
+
+// validateNotifier checks the form values against the forms of the type and runs the validation of the type.
+func validateNotifier(notify model.Notify, instance model.NotifierInstance) ([]interface{}, error) {
+	validationErrors, err := notifier.Validate(notify, instance.Data)
 	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNotifierConfig, err)
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors, ErrInvalidNotifierConfig
+	}
+	return nil, nil
+}
+
+// DeleteNotifierById deletes the notifier and removes it from all services.
+func DeleteNotifierById(ctx context.Context, id string) error {
+	if err := repository.RemoveNotifierId(ctx, id); err != nil {
+		return err
+	}
+
+	if err := repository.DeleteNotifierById(ctx, id); err != nil {
 		return err
 	}
 
-	tmpl, err := template.New(id).Parse(testNotify.GetServiceUpNotificationTemplate())
+	return LoadNotifiers(ctx)
+}
+
+// LoadNotifiers passes all persisted notifier instances to the notification system.
+func LoadNotifiers(ctx context.Context) error {
+	instances, err := repository.SelectNotifiers(ctx)
 	if err != nil {
-		log.Errorf("Unable to parse template for test notifier '%s' - '%s'", id, err)
 		return err
 	}
 
-	data := model.TemplateData{
-		Name:   "Test Service Name",
-		Date:   time.Now().Format(time.RFC3339),
-		Reason: "",
+	notifiers := make([]model.Notify, 0, len(instances))
+	for _, instance := range instances {
+		notify, err := notifier.NewNotifier(instance)
+		if err != nil {
+			log.Errorf("Unable to create notifier '%s' - '%s'", instance.Id, err)
+			continue
+		}
+		notifiers = append(notifiers, notify)
 	}
 
-	var buffer bytes.Buffer
-	if err := tmpl.Execute(&buffer, data); err != nil {
-		log.Errorf("Unable to execute template for test notifier '%s' - '%s'", id, err)
+	log.Infof("Loaded %d notifiers", len(notifiers))
+	notificationSystem.SetNotifiers(notifiers)
+	return nil
+}
+
+// SetupNotifiers imports the notifiers of the configuration file and loads all notifiers from the database.
+func SetupNotifiers(ctx context.Context) error {
+	if err := importConfiguredNotifiers(ctx); err != nil {
 		return err
 	}
+	return LoadNotifiers(ctx)
+}
+
+// importConfiguredNotifiers moves notifiers configured with NOTIFIER_<TYPE>_<KEY> keys into the database. The
+// instances keep the type as id, so the notifiers of existing services still reference them. Notifiers whose id
+// already exists are skipped, so the import can run again if NOTIFIERS_IMPORTED could not be written to the
+// configuration file, e.g. if it is read-only. The flag only saves the import on later starts.
+func importConfiguredNotifiers(ctx context.Context) error {
+	if viper.GetBool("NOTIFIERS_IMPORTED") {
+		return nil
+	}
+
+	for _, notifierType := range notifier.Types() {
+		if !isNotifierTypeConfigured(notifierType) {
+			continue
+		}
+
+		notify, err := notifier.NewNotifierOfType(notifierType, viper.GetViper())
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		imported, err := repository.InsertNotifierIfNotExists(ctx, model.NotifierInstance{
+			Id:        notifierType,
+			Type:      notifierType,
+			Name:      notify.GetName(),
+			Data:      notify.GetData(),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if imported {
+			log.Infof("Imported configured notifier '%s'", notifierType)
+		} else {
+			log.Infof("Configured notifier '%s' is already imported", notifierType)
+		}
+	}
 
-	return testNotify.SendNotification(model.Service{}, buffer.String())
+	viper.Set("NOTIFIERS_IMPORTED", true)
+	if err := viper.WriteConfig(); err != nil {
+		log.Warnf("Unable to persist that the notifiers are imported, they are checked again on the next start - '%s'", err)
+	}
+	return nil
 }
 
-func TestNotifierDownTemplate(id string, body map[string]interface{}) error {
-	log.Infof("Test notififiers down template with id '%s'", id)
+func isNotifierTypeConfigured(notifierType string) bool {
+	if viper.GetBool(fmt.Sprintf("NOTIFIER_%s_ENABLED", strings.ToUpper(notifierType))) {
+		return true
+	}
+
+	prefix := fmt.Sprintf("notifier_%s_", notifierType)
+	for _, key := range viper.AllKeys() {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNotifierUpTemplate(ctx context.Context, id string, body map[string]interface{}) error {
+	log.Infof("Test notififiers up template with id '%s'", id)
 
-	testNotify, err := setupTestNotifier(id, body)
+	testNotify, err := setupTestNotifier(ctx, id, body)
 	if err != nil {
 		return err
 	}
 
-	tmpl, err := template.New(id).Parse(testNotify.GetServiceDownNotificationTemplate())
+	downSince := time.Now().Add(-14 * time.Minute)
+	details := model.NotificationDetails{
+		StatusCode:          200,
+		LatencyInMs:         120,
+		ConsecutiveFailures: 28,
+		DownSince:           &downSince,
+	}
+	date := time.Now()
+	data := notifier.NewTemplateData(testNotifierService(), true, details, date)
+
+	message, err := notifier.RenderTemplate(testNotify, testNotify.GetServiceUpNotificationTemplate(), data)
+	if err != nil {
+		return err
+	}
+
+	return notifier.SendEvent(testNotify, model.NotificationEvent{
+		Service:          testNotifierService(),
+		IsUpNotification: true,
+		Failure:          model.Failure{ServiceId: testNotifierServiceId, Reason: data.Reason},
+		Details:          details,
+		Message:          message,
+		Date:             date,
+	})
+}
+
+func TestNotifierDownTemplate(ctx context.Context, id string, body map[string]interface{}) error {
+	log.Infof("Test notififiers down template with id '%s'", id)
+
+	testNotify, err := setupTestNotifier(ctx, id, body)
 	if err != nil {
-		log.Errorf("Unable to parse template for test notifier '%s' - '%s'", id, err)
 		return err
 	}
 
-	data := model.TemplateData{
-		Name:   "Test Service Name",
-		Date:   time.Now().Format(time.RFC3339),
-		Reason: "This is just a test",
+	downSince := time.Now().Add(-time.Minute)
+	details := model.NotificationDetails{
+		Reason:              "This is just a test",
+		StatusCode:          503,
+		ConsecutiveFailures: 2,
+		DownSince:           &downSince,
 	}
+	date := time.Now()
+	data := notifier.NewTemplateData(testNotifierService(), false, details, date)
 
-	var buffer bytes.Buffer
-	if err := tmpl.Execute(&buffer, data); err != nil {
-		log.Errorf("Unable to execute template for test notifier '%s' - '%s'", id, err)
+	message, err := notifier.RenderTemplate(testNotify, testNotify.GetServiceDownNotificationTemplate(), data)
+	if err != nil {
 		return err
 	}
 
-	return testNotify.SendNotification(model.Service{}, buffer.String())
+	return notifier.SendEvent(testNotify, model.NotificationEvent{
+		Service:          testNotifierService(),
+		IsUpNotification: false,
+		Failure:          model.Failure{ServiceId: testNotifierServiceId, Reason: data.Reason},
+		Details:          details,
+		Message:          message,
+		Date:             date,
+	})
+}
+
+func testNotifierService() model.Service {
+	return model.Service{
+		Id:       testNotifierServiceId,
+		Name:     "Test Service Name",
+		Type:     model.ServiceTypeHttp,
+		Endpoint: "https://example.com/health",
+	}
 }
 
-func setupTestNotifier(id string, body map[string]interface{}) (model.Notify, error) {
-	testStore := viper.New()
-	for k, v := range body {
-		testStore.Set(fmt.Sprintf("NOTIFIER_%s_%s", strings.ToUpper(id), strings.ToUpper(k)), v)
+// validateServiceNotificationTemplates checks that the template overrides of the service belong to existing notifiers
+// and render with sample data.
+func validateServiceNotificationTemplates(service model.Service) error {
+	downSince := time.Now().Add(-time.Minute)
+	details := model.NotificationDetails{
+		Reason:              "This is just a test",
+		StatusCode:          503,
+		LatencyInMs:         120,
+		ConsecutiveFailures: 2,
+		DownSince:           &downSince,
 	}
 
-	var testNotify model.Notify
-	switch id {
-	case "email":
-		testNotify = notifier.NewEMailNotifier(testStore)
-	case "telegram":
-		testNotify = notifier.NewTelegramNotifier(testStore)
-	default:
-		return nil, fmt.Errorf("notifier with id '%s' is unknown", id)
+	for _, notificationTemplate := range service.NotificationTemplates {
+		notify, err := notificationSystem.GetNotifierById(notificationTemplate.NotifierId)
+		if err != nil {
+			return fmt.Errorf("%w: '%s'", ErrUnknownNotifier, notificationTemplate.NotifierId)
+		}
+
+		templates := map[bool]string{true: notificationTemplate.UpTemplate, false: notificationTemplate.DownTemplate}
+		for isUpNotification, templateText := range templates {
+			if len(templateText) == 0 {
+				continue
+			}
+
+			data := notifier.NewTemplateData(testNotifierService(), isUpNotification, details, time.Now())
+			if _, err := notifier.RenderTemplate(notify, templateText, data); err != nil {
+				return fmt.Errorf("%w for notifier '%s': %s", ErrInvalidNotificationTemplate, notificationTemplate.NotifierId, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setupTestNotifier creates a notifier with the unsaved form values. The id is either the id of an instance or the
+// type of a notifier that is not created yet.
+func setupTestNotifier(ctx context.Context, id string, body map[string]interface{}) (model.Notify, error) {
+	notifierType := id
+	if instance, err := repository.SelectNotifierById(ctx, id); err == nil {
+		notifierType = instance.Type
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
 	}
 
-	return testNotify, nil
+	return notifier.NewNotifierOfType(notifierType, notifier.NewStore(notifierType, body))
 }
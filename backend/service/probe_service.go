@@ -0,0 +1,198 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bodyExcerptLength = 1024
+
+	assertionStatusCode   = "statusCode"
+	assertionResponseBody = "responseBody"
+)
+
+var (
+	ErrInvalidServiceDefinition = errors.New("invalid service definition")
+)
+
+// TestService validates the service definition and runs its probe once without storing anything.
+// Validation problems are returned as list next to ErrInvalidServiceDefinition.
+func TestService(service model.Service) (model.ProbeResult, []interface{}, error) {
+	if validationErrors := validateServiceDefinition(service); len(validationErrors) > 0 {
+		return model.ProbeResult{}, validationErrors, ErrInvalidServiceDefinition
+	}
+
+	logger := log.WithFields(log.Fields{"serviceName": service.Name, "dryRun": true})
+
+	switch service.Type {
+	case model.ServiceTypeHttp:
+		logger.Infof("Testing service '%s' as type HTTP", service.Name)
+		result, err := probeHttpService(service, true)
+		return result, nil, err
+	default:
+		check, failure, err := executeServiceCheck(logger, service)
+		if err != nil || check == nil {
+			return model.ProbeResult{}, nil, err
+		}
+
+		result := model.ProbeResult{
+			IsFailure:   check.IsFailure,
+			LatencyInMs: check.LatencyInMs,
+			Assertions:  make([]model.AssertionResult, 0),
+		}
+		if failure != nil {
+			result.Reason = failure.Reason
+		}
+		return result, nil, nil
+	}
+}
+
+func validateServiceDefinition(service model.Service) []interface{} {
+	result := make([]interface{}, 0)
+
+	if service.Type != model.ServiceTypeHttp {
+		return result
+	}
+
+	if len(service.ExpectedHttpResponseBody) > 0 {
+		if _, err := regexp.Compile(service.ExpectedHttpResponseBody); err != nil {
+			result = append(result, fmt.Sprintf("expectedHttpResponseBody is not a valid regular expression: %s", err))
+		}
+	}
+
+	for _, header := range strings.Split(service.HttpHeaders, ";") {
+		if len(strings.TrimSpace(header)) == 0 {
+			continue
+		}
+
+		if len(strings.Split(header, ":")) != 2 {
+			result = append(result, fmt.Sprintf("http header '%s' is not in the format 'key:value' and will be ignored", header))
+		}
+	}
+
+	if _, err := http.NewRequest(service.HttpMethod, service.Endpoint, nil); err != nil {
+		result = append(result, fmt.Sprintf("unable to create request: %s", err))
+	}
+
+	return result
+}
+
+// probeHttpService executes the http request of the service and evaluates all assertions.
+// The response body is only read if it has to be matched or captureBody is set.
+func probeHttpService(service model.Service, captureBody bool) (model.ProbeResult, error) {
+	client := http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: !service.VerifySsl,
+			},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !service.FollowRedirects {
+				return fmt.Errorf("i am not allowed to follow redirects")
+			}
+			return nil
+		},
+		Timeout: time.Duration(service.RequestTimeoutInSeconds) * time.Second,
+	}
+
+	request, err := http.NewRequest(service.HttpMethod, service.Endpoint, strings.NewReader(service.HttpBody))
+	if err != nil {
+		return model.ProbeResult{}, err
+	}
+
+	headers := strings.Split(service.HttpHeaders, ";")
+	for _, header := range headers {
+		headerValues := strings.Split(header, ":")
+		if len(headerValues) != 2 {
+			continue
+		}
+
+		headerKey := headerValues[0]
+		headerValue := headerValues[1]
+
+		request.Header.Add(headerKey, headerValue)
+	}
+
+	result := model.ProbeResult{Assertions: make([]model.AssertionResult, 0)}
+	fail := func(reason string) {
+		if !result.IsFailure {
+			result.IsFailure = true
+			result.Reason = reason
+		}
+	}
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		result.LatencyInMs = time.Since(start).Milliseconds()
+		fail(err.Error())
+		return result, nil
+	}
+	defer response.Body.Close()
+
+	result.LatencyInMs = time.Since(start).Milliseconds()
+	result.StatusCode = response.StatusCode
+
+	statusCodeMatched := response.StatusCode == service.ExpectedHttpStatusCode
+	result.Assertions = append(result.Assertions, model.AssertionResult{
+		Name:     assertionStatusCode,
+		Expected: strconv.Itoa(service.ExpectedHttpStatusCode),
+		Actual:   strconv.Itoa(response.StatusCode),
+		Passed:   statusCodeMatched,
+	})
+	if !statusCodeMatched {
+		fail(fmt.Sprintf("Expected status code '%d' but got '%d'", service.ExpectedHttpStatusCode, response.StatusCode))
+		if !captureBody {
+			return result, nil
+		}
+	}
+
+	if len(service.ExpectedHttpResponseBody) == 0 && !captureBody {
+		return result, nil
+	}
+
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		fail(fmt.Sprintf("Unable to read response body: %s", err.Error()))
+		return result, nil
+	}
+
+	if len(bodyBytes) > bodyExcerptLength {
+		result.BodyExcerpt = string(bodyBytes[:bodyExcerptLength])
+	} else {
+		result.BodyExcerpt = string(bodyBytes)
+	}
+
+	if len(service.ExpectedHttpResponseBody) > 0 {
+		assertion := model.AssertionResult{
+			Name:     assertionResponseBody,
+			Expected: service.ExpectedHttpResponseBody,
+		}
+
+		matched, err := regexp.Match(service.ExpectedHttpResponseBody, bodyBytes)
+		if err != nil {
+			assertion.Actual = err.Error()
+			fail(fmt.Sprintf("Unable to read response body: %s", err.Error()))
+		} else if !matched {
+			assertion.Actual = "no match"
+			fail(fmt.Sprintf("Body did not match '%s'", service.ExpectedHttpResponseBody))
+		} else {
+			assertion.Actual = "match"
+			assertion.Passed = true
+		}
+
+		result.Assertions = append(result.Assertions, assertion)
+	}
+
+	return result, nil
+}
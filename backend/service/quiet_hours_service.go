@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/notifier"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	ErrInvalidNotificationSchedule = errors.New("invalid notification schedule")
+)
+
+// validateNotificationSchedule checks the schedule of the notifier with the given id. The fallback notifier must
+// exist and must not be the notifier itself.
+func validateNotificationSchedule(notifierId string, schedule *model.NotificationSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidNotificationSchedule, err)
+	}
+
+	if len(schedule.FallbackNotifierId) == 0 {
+		return nil
+	}
+
+	if schedule.FallbackNotifierId == notifierId {
+		return fmt.Errorf("%w: a notifier can not be its own fallback", ErrInvalidNotificationSchedule)
+	}
+
+	if _, err := notificationSystem.GetNotifierById(schedule.FallbackNotifierId); err != nil {
+		return fmt.Errorf("%w: '%s'", ErrUnknownNotifier, schedule.FallbackNotifierId)
+	}
+	return nil
+}
+
+// applyQuietHours holds the notification or routes it to the fallback notifier if its notifier is in quiet hours.
+// Digests and notifications of services with a critical tag are never held. It returns true if the notification
+// must not be sent now.
+func applyQuietHours(ctx context.Context, tx *sql.Tx, logger *log.Entry, notification *model.Notification) (bool, error) {
+	if notification.IsDigest {
+		return false, nil
+	}
+
+	recipient, err := notificationSystem.GetNotifierById(notification.NotifierId)
+	if err != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	schedule := recipient.GetSchedule()
+	if schedule == nil || schedule.IsActive(now) {
+		return false, nil
+	}
+
+	service, err := repository.SelectServiceById(ctx, notification.ServiceId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("unable to get service: %w", err)
+		}
+		// the service was deleted after the notification was queued
+		service = model.Service{Id: notification.ServiceId, Name: notification.ServiceName}
+	}
+
+	if schedule.IsCritical(service) {
+		return false, nil
+	}
+
+	fallback, err := notificationSystem.GetNotifierById(schedule.FallbackNotifierId)
+	if err != nil || !fallback.IsEnabled() || (fallback.GetSchedule() != nil && !fallback.GetSchedule().IsActive(now)) {
+		notification.Status = model.NotificationStatusHeld
+		return true, nil
+	}
+
+	routed := model.NewNotification(service, fallback.GetId(), notification.IsUpNotification, notification.Details)
+
+	payload, err := notifier.RenderNotification(fallback, service, notification.IsUpNotification, notification.Details,
+		notification.CreatedAt)
+	if err != nil {
+		logger.Errorf("Unable to render notification for notifier '%s' - '%s'", fallback.GetId(), err)
+		routed.Status = model.NotificationStatusFailed
+		routed.LastError = err.Error()
+	}
+	routed.Payload = payload
+
+	if err := repository.InsertNotificationTx(ctx, tx, routed); err != nil {
+		return false, err
+	}
+
+	notification.Status = model.NotificationStatusSkipped
+	notification.LastError = fmt.Sprintf("routed to fallback notifier '%s' during quiet hours", fallback.GetId())
+	return true, nil
+}
+
+// queueQuietHourSummaries sends the notifications held during quiet hours once their notifier is active again.
+// Notifiers that support digests get a summary, all others every held notification.
+func queueQuietHourSummaries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notifierIds, err := repository.SelectHeldNotifierIds(ctx)
+	if err != nil {
+		log.Errorf("Unable to get held notifications: '%s'", err)
+		return
+	}
+
+	for _, notifierId := range notifierIds {
+		recipient, err := notificationSystem.GetNotifierById(notifierId)
+		if err != nil {
+			continue
+		}
+
+		if schedule := recipient.GetSchedule(); schedule != nil && !schedule.IsActive(time.Now()) {
+			continue
+		}
+
+		if _, ok := recipient.(model.DigestNotifier); ok {
+			queueDigest(notifierId, model.NotificationStatusHeld)
+			continue
+		}
+
+		log.Infof("Quiet hours of notifier '%s' ended. Releasing held notifications", notifierId)
+		if err := repository.ReleaseHeldNotifications(ctx, notifierId); err != nil {
+			log.Errorf("Unable to release held notifications of notifier '%s': '%s'", notifierId, err)
+		}
+	}
+}
@@ -2,16 +2,13 @@ package service
 
 import (
 	"context"
-	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/koloo91/monhttp/model"
-	"github.com/koloo91/monhttp/notifier"
 	"github.com/koloo91/monhttp/repository"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"io/ioutil"
-	"net/http"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -19,6 +16,8 @@ import (
 	"time"
 )
 
+var ErrServicePaused = errors.New("service is paused, its result can not be persisted")
+
 func StartScheduleJob(enabled bool) {
 	if !enabled {
 		log.Info("Job scheduler is disabled")
@@ -100,6 +99,14 @@ func ProcessService(workerId int, jobId string) {
 		return
 	}
 
+	if !service.Enabled {
+		logger.Infof("Service '%s' is paused. Skipping check", service.Name)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
 	executeAt := time.Now().Add(time.Duration(service.IntervalInSeconds) * time.Second)
 	logger.Infof("Set next check time of service '%s' to %s", service.Name, executeAt.String())
 
@@ -111,84 +118,239 @@ func ProcessService(workerId int, jobId string) {
 		return
 	}
 
-	var check *model.Check
-	var failure *model.Failure
-	var checkErr error
+	maintenance, err := getActiveMaintenance(ctx, service)
+	if err != nil {
+		logger.Errorf("Unable to get active maintenance for service '%s' - '%s'", service.Name, err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if maintenance != nil && maintenance.SkipChecks {
+		logger.Infof("Service '%s' is in maintenance '%s'. Skipping check", service.Name, maintenance.Name)
+		if err := tx.Commit(); err != nil {
+			logger.Errorf("Error commiting transaction: '%s'", err)
+		}
+		return
+	}
+
+	check, failure, err := executeServiceCheck(logger, service)
+	if err != nil {
+		logger.Errorf("Error handling service type: '%s' - '%s'", service.Name, err)
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if err := storeCheckResult(ctx, tx, logger, service, maintenance, check, failure); err != nil {
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Errorf("Error commiting transaction: '%s'", err)
+	}
+}
+
+// CheckServiceNow runs the probe of the service immediately. If persist is set, the result is stored and
+// notifications are queued exactly like for a scheduled check. The schedule of the service is not changed. The result
+// of a paused service can not be persisted, so that it does not store checks or send notifications.
+func CheckServiceNow(ctx context.Context, serviceId string, persist bool) (*model.Check, *model.Failure, error) {
+	logger := log.WithFields(log.Fields{"serviceId": serviceId, "persist": persist})
+
+	service, err := repository.SelectServiceById(ctx, serviceId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if persist && !service.Enabled {
+		return nil, nil, ErrServicePaused
+	}
+
+	check, failure, err := executeServiceCheck(logger, service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !persist {
+		return check, failure, nil
+	}
+
+	maintenance, err := getActiveMaintenance(ctx, service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := repository.BeginnTransaction()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := storeCheckResult(ctx, tx, logger, service, maintenance, check, failure); err != nil {
+		if err := tx.Rollback(); err != nil {
+			logger.Errorf("Error rolling back transaction: '%s'", err)
+		}
+		return nil, nil, err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return check, failure, nil
+}
+
+func executeServiceCheck(logger *log.Entry, service model.Service) (*model.Check, *model.Failure, error) {
 	switch service.Type {
 	case model.ServiceTypeHttp:
 		logger.Infof("Processing service '%s' as type HTTP", service.Name)
-		check, failure, checkErr = handleHttpServiceType(service)
+		return handleHttpServiceType(service)
 	case model.ServiceTypeIcmpPing:
 		logger.Infof("Processing service '%s' as type ICMP Ping", service.Name)
-		check, failure, checkErr = handleIcmpPingServiceType(service)
+		return handleIcmpPingServiceType(service)
 	default:
 		logger.Warnf("Unknown service type '%s'", service.Type)
+		return nil, nil, nil
 	}
+}
 
-	if checkErr != nil {
-		logger.Errorf("Error handling service type: '%s' - '%s'", service.Name, err)
-		if err := tx.Rollback(); err != nil {
-			logger.Errorf("Error rolling back transaction: '%s'", err)
+// storeCheckResult queues the notifications the check result triggers and inserts the check and failure.
+// The caller is responsible for rolling back the transaction if an error is returned.
+func storeCheckResult(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, maintenance *model.Maintenance,
+	check *model.Check, failure *model.Failure) error {
+	if maintenance != nil {
+		logger.Infof("Service '%s' is in maintenance '%s'. Flagging check and suppressing notifications", service.Name, maintenance.Name)
+		if check != nil {
+			check.IsMaintenance = true
 		}
-		return
+		failure = nil
 	}
 
+	if failure != nil && len(service.ParentIds) > 0 {
+		parent, err := findDownParent(ctx, service)
+		if err != nil {
+			logger.Errorf("Unable to determine if a dependency of service '%s' is down - '%s'", service.Name, err)
+			return err
+		}
+
+		if parent != nil {
+			logger.Infof("Dependency '%s' of service '%s' is down. Suppressing notifications", parent.Name, service.Name)
+			failure.Reason = fmt.Sprintf("Dependency '%s' is down: %s", parent.Name, failure.Reason)
+			if check != nil {
+				check.IsDependencyDown = true
+			}
+		}
+	}
+
+	isFlapping, err := updateFlapping(ctx, tx, logger, service, check, failure)
+	if err != nil {
+		logger.Errorf("Unable to determine if service '%s' is flapping - '%s'", service.Name, err)
+		return err
+	}
+
+	// flapping services and services that only alert on SLO burn do not send individual up and down notifications
+	suppressNotifications := isFlapping || (service.SloAlertsOnly && service.SloTarget > 0)
+
 	if failure != nil {
-		if service.EnableNotifications {
+		incident, err := openIncident(ctx, tx, logger, service, failure.Reason)
+		if err != nil {
+			logger.Errorf("Unable to open incident for service '%s' - '%s'", service.Name, err)
+			return err
+		}
+		// the down notification is sent with the same number of failures that opens the incident
+		if incident != nil {
+			failure.IncidentId = incident.Id
+		}
+
+		if incident != nil && service.EnableNotifications && !suppressNotifications && (check == nil || !check.IsDependencyDown) {
 			logger.Infof("Notifications for service '%s' enabled", service.Name)
 			sendFailureNotification, err := shouldSendFailureNotification(ctx, tx, service)
 			if err != nil {
 				logger.Errorf("Unable to determine if we should send a notfication for service '%s' - '%s'", service.Name, err)
-				if err := tx.Rollback(); err != nil {
-					logger.Errorf("Error rolling back transaction: '%s'", err)
-				}
-				return
+				return err
 			}
 
 			if sendFailureNotification {
 				logger.Infof("Sending notification for service '%s'", service.Name)
-				notificationSystem.AddNotification(notifier.NewNotification(service, false, *failure))
+				details := model.NotificationDetails{
+					Reason:              failure.Reason,
+					ConsecutiveFailures: incident.FailureCount + 1,
+					DownSince:           &incident.CreatedAt,
+				}
+				if check != nil {
+					details.StatusCode = check.StatusCode
+				}
+
+				if err := notifyServiceDown(ctx, tx, logger, service, *incident, details); err != nil {
+					logger.Errorf("Unable to queue notifications for service '%s' - '%s'", service.Name, err)
+					return err
+				}
 			}
 		}
 
 		if err := repository.InsertFailure(ctx, tx, *failure); err != nil {
 			logger.Errorf("Unable to insert failure for service '%s' - '%s'", service.Name, err)
-			if err := tx.Rollback(); err != nil {
-				logger.Errorf("Error rolling back transaction: '%s'", err)
-			}
-			return
+			return err
 		}
 	}
 
 	if check != nil {
-		if service.EnableNotifications && !check.IsFailure {
-			sendUpNotification, err := shouldSendUpNotification(ctx, tx, service)
+		if !check.IsFailure && !check.IsMaintenance {
+			incident, err := resolveIncident(ctx, tx, logger, service)
 			if err != nil {
-				logger.Errorf("Unable to determine if we should send a notfication for service '%s' - '%s'", service.Name, err)
-				if err := tx.Rollback(); err != nil {
-					logger.Errorf("Error rolling back transaction: '%s'", err)
+				logger.Errorf("Unable to resolve incident of service '%s' - '%s'", service.Name, err)
+				return err
+			}
+
+			notifierIds := service.Notifiers
+			details := model.NotificationDetails{StatusCode: check.StatusCode, LatencyInMs: check.LatencyInMs}
+			if incident != nil {
+				notifierIds, err = recoveryNotifierIds(ctx, tx, service, *incident)
+				if err != nil {
+					return err
+				}
+				details.ConsecutiveFailures = incident.FailureCount
+				details.DownSince = &incident.CreatedAt
+			}
+
+			sendUpNotification := false
+			if service.EnableNotifications && !suppressNotifications {
+				sendUpNotification, err = shouldSendUpNotification(ctx, tx, service)
+				if err != nil {
+					logger.Errorf("Unable to determine if we should send a notfication for service '%s' - '%s'", service.Name, err)
+					return err
 				}
-				return
 			}
 
 			if sendUpNotification {
-				notificationSystem.AddNotification(notifier.NewNotification(service, true, model.Failure{}))
+				if err := queueNotifications(ctx, tx, logger, service, notifierIds, true, details); err != nil {
+					logger.Errorf("Unable to queue notifications for service '%s' - '%s'", service.Name, err)
+					return err
+				}
 			}
 		}
 
 		if err := repository.InsertCheck(ctx, tx, *check); err != nil {
 			logger.Errorf("Unable to insert check for service '%s' - '%s'", service.Name, err)
-			if err := tx.Rollback(); err != nil {
-				logger.Errorf("Error rolling back transaction: '%s'", err)
-			}
-			return
+			return err
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
-		logger.Errorf("Error commiting transaction: '%s'", err)
+		if err := updateSloBurn(ctx, tx, logger, service, check); err != nil {
+			logger.Errorf("Unable to determine the error budget burn of service '%s' - '%s'", service.Name, err)
+			return err
+		}
+
+		if err := updateLatencyDegradation(ctx, tx, logger, service, check); err != nil {
+			logger.Errorf("Unable to determine if the latency of service '%s' is degraded - '%s'", service.Name, err)
+			return err
+		}
 	}
+
+	return nil
 }
 
 func shouldSendUpNotification(ctx context.Context, tx *sql.Tx, service model.Service) (bool, error) {
@@ -216,18 +378,24 @@ func shouldSendUpNotification(ctx context.Context, tx *sql.Tx, service model.Ser
 	return false, nil
 }
 
-func shouldSendFailureNotification(ctx context.Context, tx *sql.Tx, service model.Service) (bool, error) {
-	checks, err := repository.GetLastNChecksTx(ctx, tx, service.Id, service.NotifyAfterNumberOfFailures)
-	if err != nil {
-		return false, err
-	}
-
+// countFailures returns the number of failed checks.
+func countFailures(checks []model.Check) int {
 	counter := 0
 	for _, check := range checks {
 		if check.IsFailure {
 			counter++
 		}
 	}
+	return counter
+}
+
+func shouldSendFailureNotification(ctx context.Context, tx *sql.Tx, service model.Service) (bool, error) {
+	checks, err := repository.GetLastNChecksTx(ctx, tx, service.Id, service.NotifyAfterNumberOfFailures)
+	if err != nil {
+		return false, err
+	}
+
+	counter := countFailures(checks)
 
 	sendNotification := false
 	if service.ContinuouslySendNotifications {
@@ -239,80 +407,13 @@ func shouldSendFailureNotification(ctx context.Context, tx *sql.Tx, service mode
 }
 
 func handleHttpServiceType(service model.Service) (*model.Check, *model.Failure, error) {
-	client := http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: !service.VerifySsl,
-			},
-		},
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if !service.FollowRedirects {
-				return fmt.Errorf("i am not allowed to follow redirects")
-			}
-			return nil
-		},
-		Timeout: time.Duration(service.RequestTimeoutInSeconds) * time.Second,
-	}
-
-	request, err := http.NewRequest(service.HttpMethod, service.Endpoint, strings.NewReader(service.HttpBody))
+	result, err := probeHttpService(service, false)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	headers := strings.Split(service.HttpHeaders, ";")
-	for _, header := range headers {
-		headerValues := strings.Split(header, ":")
-		if len(headerValues) != 2 {
-			continue
-		}
-
-		headerKey := headerValues[0]
-		headerValue := headerValues[1]
-
-		request.Header.Add(headerKey, headerValue)
-	}
-
-	start := time.Now()
-	response, err := client.Do(request)
-	if err != nil {
-		return model.NewCheck(service.Id, 0, true), model.NewFailure(service.Id, err.Error()), nil
-	}
-	defer response.Body.Close()
-
-	latency := time.Since(start)
-
-	if response.StatusCode != service.ExpectedHttpStatusCode {
-		reason := fmt.Sprintf("Expected status code '%d' but got '%d'", service.ExpectedHttpStatusCode, response.StatusCode)
-		failure := model.NewFailure(service.Id, reason)
-
-		check := model.NewCheck(service.Id, 0, true)
-		return check, failure, nil
-	}
-
-	if len(service.ExpectedHttpResponseBody) > 0 {
-		bodyBytes, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			reason := fmt.Sprintf("Unable to read response body: %s", err.Error())
-			failure := model.NewFailure(service.Id, reason)
-			return model.NewCheck(service.Id, 0, true), failure, nil
-		} else {
-			matched, err := regexp.Match(service.ExpectedHttpResponseBody, bodyBytes)
-			if err != nil {
-				reason := fmt.Sprintf("Unable to read response body: %s", err.Error())
-				failure := model.NewFailure(service.Id, reason)
-				return model.NewCheck(service.Id, 0, true), failure, nil
-			}
-
-			if !matched {
-				reason := fmt.Sprintf("Body did not match '%s'", service.ExpectedHttpResponseBody)
-				failure := model.NewFailure(service.Id, reason)
-				return model.NewCheck(service.Id, 0, true), failure, nil
-			}
-		}
-
-	}
-
-	return model.NewCheck(service.Id, latency.Milliseconds(), false), nil, nil
+	check, failure := result.ToCheckAndFailure(service.Id)
+	return check, failure, nil
 }
 
 func handleIcmpPingServiceType(service model.Service) (*model.Check, *model.Failure, error) {
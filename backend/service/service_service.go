@@ -7,6 +7,18 @@ import (
 )
 
 func CreateService(ctx context.Context, service model.Service) (model.Service, error) {
+	if err := validateServiceDependencies(ctx, service); err != nil {
+		return model.Service{}, err
+	}
+
+	if err := validateServiceEscalationPolicy(ctx, service); err != nil {
+		return model.Service{}, err
+	}
+
+	if err := validateServiceNotificationTemplates(service); err != nil {
+		return model.Service{}, err
+	}
+
 	tx, err := repository.BeginnTransaction()
 	if err != nil {
 		return model.Service{}, err
@@ -46,13 +58,62 @@ func GetServiceById(ctx context.Context, id string) (model.Service, error) {
 }
 
 func UpdateServiceById(ctx context.Context, id string, service model.Service) (model.Service, error) {
+	service.Id = id
+	if err := validateServiceDependencies(ctx, service); err != nil {
+		return model.Service{}, err
+	}
+
+	if err := validateServiceEscalationPolicy(ctx, service); err != nil {
+		return model.Service{}, err
+	}
+
+	if err := validateServiceNotificationTemplates(service); err != nil {
+		return model.Service{}, err
+	}
+
 	if err := repository.UpdateServiceById(ctx, id, service); err != nil {
-		return model.Service{}, nil
+		return model.Service{}, err
 	}
 
 	return repository.SelectServiceById(ctx, id)
 }
 
 func DeleteServiceById(ctx context.Context, id string) error {
+	if err := repository.RemoveParentId(ctx, id); err != nil {
+		return err
+	}
 	return repository.DeleteServiceById(ctx, id)
 }
+
+func PauseServiceById(ctx context.Context, id string) (model.Service, error) {
+	return setServiceEnabledById(ctx, id, false)
+}
+
+func ResumeServiceById(ctx context.Context, id string) (model.Service, error) {
+	return setServiceEnabledById(ctx, id, true)
+}
+
+// PauseServices pauses the given services or all services and returns the number of affected services.
+func PauseServices(ctx context.Context, ids []string, all bool) (int64, error) {
+	return setServicesEnabled(ctx, ids, all, false)
+}
+
+// ResumeServices resumes the given services or all services and returns the number of affected services.
+func ResumeServices(ctx context.Context, ids []string, all bool) (int64, error) {
+	return setServicesEnabled(ctx, ids, all, true)
+}
+
+func setServiceEnabledById(ctx context.Context, id string, enabled bool) (model.Service, error) {
+	if err := repository.UpdateServiceEnabledById(ctx, id, enabled); err != nil {
+		return model.Service{}, err
+	}
+
+	return repository.SelectServiceById(ctx, id)
+}
+
+func setServicesEnabled(ctx context.Context, ids []string, all, enabled bool) (int64, error) {
+	if all {
+		return repository.UpdateAllServicesEnabled(ctx, enabled)
+	}
+	return repository.UpdateServicesEnabledByIds(ctx, ids, enabled)
+}
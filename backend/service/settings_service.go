@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"github.com/koloo91/monhttp/model"
 	log "github.com/sirupsen/logrus"
@@ -45,6 +46,11 @@ func UpdateSettings(settings model.SettingsVo) error {
 		return err
 	}
 
+	if err := SetupNotifiers(context.Background()); err != nil {
+		log.Errorf("Unable to setup notifiers: '%s'", err)
+		return err
+	}
+
 	log.Info("Writing new settings into config")
 	return viper.WriteConfig()
 }
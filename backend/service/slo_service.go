@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/koloo91/monhttp/model"
+	"github.com/koloo91/monhttp/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	ErrSloNotConfigured = errors.New("service has no SLO")
+)
+
+type uptimeSelector func(ctx context.Context, serviceId string, from, to time.Time) (float64, float64, error)
+
+// updateSloBurn compares the error budget burn rate of the service over the last hour with the fast burn rate and
+// over the last 6 hours with the slow burn rate. A down notification is queued when a burn rate is exceeded, an up
+// notification once neither is exceeded anymore. The check must already be stored.
+func updateSloBurn(ctx context.Context, tx *sql.Tx, logger *log.Entry, service model.Service, check *model.Check) error {
+	if service.SloTarget == 0 {
+		if service.SloBurnState != model.SloBurnStateNone {
+			return repository.UpdateServiceSloBurnStateTx(ctx, tx, service.Id, model.SloBurnStateNone)
+		}
+		return nil
+	}
+
+	if check == nil || check.IsMaintenance {
+		return nil
+	}
+
+	status, err := computeSloStatus(ctx, service, time.Now(), func(ctx context.Context, serviceId string, from, to time.Time) (float64, float64, error) {
+		return repository.SelectUptimeTx(ctx, tx, serviceId, from, to)
+	})
+	if err != nil {
+		return err
+	}
+
+	state := model.SloBurnStateNone
+	if status.FastBurnRate >= status.FastBurnRateThreshold {
+		state = model.SloBurnStateFast
+	} else if status.SlowBurnRate >= status.SlowBurnRateThreshold {
+		state = model.SloBurnStateSlow
+	}
+
+	if state == service.SloBurnState {
+		return nil
+	}
+
+	if err := repository.UpdateServiceSloBurnStateTx(ctx, tx, service.Id, state); err != nil {
+		return err
+	}
+
+	switch {
+	case state == model.SloBurnStateFast:
+		logger.Infof("Error budget of service '%s' burns fast with %.1fx", service.Name, status.FastBurnRate)
+		reason := fmt.Sprintf("Fast error budget burn: %.1fx over the last hour exceeds %.1fx (SLO %g%% over %d days, %.0f%% of the budget remaining)",
+			status.FastBurnRate, status.FastBurnRateThreshold, status.Target, status.WindowInDays, status.ErrorBudgetRemaining*100)
+		return queueServiceNotifications(ctx, tx, logger, service, false, model.NotificationDetails{Reason: reason})
+	case state == model.SloBurnStateSlow && service.SloBurnState == model.SloBurnStateNone:
+		logger.Infof("Error budget of service '%s' burns slowly with %.1fx", service.Name, status.SlowBurnRate)
+		reason := fmt.Sprintf("Slow error budget burn: %.1fx over the last 6 hours exceeds %.1fx (SLO %g%% over %d days, %.0f%% of the budget remaining)",
+			status.SlowBurnRate, status.SlowBurnRateThreshold, status.Target, status.WindowInDays, status.ErrorBudgetRemaining*100)
+		return queueServiceNotifications(ctx, tx, logger, service, false, model.NotificationDetails{Reason: reason})
+	case state == model.SloBurnStateNone:
+		logger.Infof("Error budget burn of service '%s' is back to normal", service.Name)
+		reason := fmt.Sprintf("Error budget burn is back to normal (%.0f%% of the budget remaining)", status.ErrorBudgetRemaining*100)
+		return queueServiceNotifications(ctx, tx, logger, service, true, model.NotificationDetails{Reason: reason})
+	}
+
+	// a fast burn that slowed down is still covered by the fast burn notification
+	return nil
+}
+
+func computeSloStatus(ctx context.Context, service model.Service, now time.Time, selectUptime uptimeSelector) (model.SloStatus, error) {
+	fastThreshold, slowThreshold := service.SloBurnRateThresholds()
+	window := service.SloWindow()
+
+	status := model.SloStatus{
+		Target:                service.SloTarget,
+		WindowInDays:          int(window.Hours() / 24),
+		FastBurnRateThreshold: fastThreshold,
+		SlowBurnRateThreshold: slowThreshold,
+		BurnState:             service.SloBurnState,
+	}
+
+	success, failures, err := selectUptime(ctx, service.Id, now.Add(-window), now)
+	if err != nil {
+		return model.SloStatus{}, err
+	}
+	if success+failures > 0 {
+		status.Uptime = (success / (success + failures)) * 100
+	}
+	status.ErrorBudgetRemaining = 1 - model.BurnRate(service.SloTarget, success, failures)
+
+	success, failures, err = selectUptime(ctx, service.Id, now.Add(-model.SloFastBurnWindow), now)
+	if err != nil {
+		return model.SloStatus{}, err
+	}
+	status.FastBurnRate = model.BurnRate(service.SloTarget, success, failures)
+
+	success, failures, err = selectUptime(ctx, service.Id, now.Add(-model.SloSlowBurnWindow), now)
+	if err != nil {
+		return model.SloStatus{}, err
+	}
+	status.SlowBurnRate = model.BurnRate(service.SloTarget, success, failures)
+
+	return status, nil
+}
+
+// GetSloStatus returns the error budget and the burn rates of the service.
+func GetSloStatus(ctx context.Context, serviceId string) (model.SloStatus, error) {
+	service, err := repository.SelectServiceById(ctx, serviceId)
+	if err != nil {
+		return model.SloStatus{}, err
+	}
+
+	if service.SloTarget == 0 {
+		return model.SloStatus{}, ErrSloNotConfigured
+	}
+
+	return computeSloStatus(ctx, service, time.Now(), repository.SelectUptime)
+}
@@ -1,7 +1,8 @@
-import {Component, Input, OnInit} from '@angular/core';
+import {Component, EventEmitter, Input, OnInit, Output} from '@angular/core';
 import {Notifier} from '../../models/notifier.model';
 import {FormBuilder, FormGroup} from '@angular/forms';
 import {NotifierService} from '../../services/notifier.service';
+import {ApiError, FieldError} from '../../models/api-error.model';
 
 @Component({
   selector: 'app-notifier-settings',
@@ -19,6 +20,9 @@ export class NotifierSettingsComponent implements OnInit {
     this._notifier = notifier;
   }
 
+  @Output()
+  saved = new EventEmitter<Notifier>();
+
   _notifier: Notifier
 
   notifierFormGroup: FormGroup = this.fb.group({});
@@ -32,15 +36,34 @@ export class NotifierSettingsComponent implements OnInit {
   }
 
   updateNotifier(): void {
-    this.notifierService.put(this._notifier.id, this.notifierFormGroup.value)
-      .subscribe(
-        console.log,
-        console.log
-      );
+    const request = this._notifier.id ?
+      this.notifierService.put(this._notifier.id, this._notifier.name, this.notifierFormGroup.value,
+        this._notifier.rateLimit, this._notifier.schedule) :
+      this.notifierService.post(this._notifier.type, this._notifier.name, this.notifierFormGroup.value);
+
+    request.subscribe(
+      notifier => this.saved.emit(notifier),
+      (error: ApiError) => this.setFieldErrors(error)
+    );
+  }
+
+  // notifiers that are not created yet are tested with their type
+  get testId(): string {
+    return this._notifier.id || this._notifier.type;
+  }
+
+  setFieldErrors(error: ApiError): void {
+    (error.errors || []).forEach((fieldError: FieldError) => {
+      const control = this.notifierFormGroup.get(fieldError.field);
+      if (control) {
+        control.setErrors({server: fieldError.message});
+        control.markAsTouched();
+      }
+    });
   }
 
   testUpTemplate(): void {
-    this.notifierService.testUpTemplate(this._notifier.id, this.notifierFormGroup.value)
+    this.notifierService.testUpTemplate(this.testId, this.notifierFormGroup.value)
       .subscribe(
         console.log,
         console.log
@@ -48,7 +71,7 @@ export class NotifierSettingsComponent implements OnInit {
   }
 
   testDownTemplate(): void {
-    this.notifierService.testDownTemplate(this._notifier.id, this.notifierFormGroup.value)
+    this.notifierService.testDownTemplate(this.testId, this.notifierFormGroup.value)
       .subscribe(
         console.log,
         console.log
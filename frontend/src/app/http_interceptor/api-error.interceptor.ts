@@ -35,7 +35,7 @@ export class ApiErrorInterceptor implements HttpInterceptor {
       const apiError: ApiError = {message: error.error.message};
       return throwError(apiError);
     } else {
-      const apiError: ApiError = {message: error.statusText};
+      const apiError: ApiError = {message: error.statusText, errors: error.error && error.error.errors};
       return throwError(apiError);
     }
   }
@@ -1,3 +1,9 @@
 export interface ApiError {
   message: string;
+  errors?: any[];
+}
+
+export interface FieldError {
+  field: string;
+  message: string;
 }
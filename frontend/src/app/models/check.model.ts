@@ -3,5 +3,7 @@ export interface Check {
   serviceId: string;
   latencyInMs: number;
   isFailure: boolean;
+  isMaintenance: boolean;
+  isDependencyDown: boolean;
   createdAt: string;
 }
@@ -1,6 +1,7 @@
 export interface Failure {
   id: string;
   serviceId: string;
+  incidentId?: string;
   reason: string;
   createdAt: string;
 }
@@ -1,3 +1,5 @@
 export interface IsOnline {
   online: boolean;
+  flapping: boolean;
+  degraded: boolean;
 }
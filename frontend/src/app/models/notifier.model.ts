@@ -1,14 +1,33 @@
 export interface Notifier {
   id: string;
+  type: string;
   name: string;
+  rateLimit?: number;
+  schedule?: NotificationSchedule;
   data: any;
   form: NotifierForm[];
 }
 
+export interface NotifierType {
+  type: string;
+  name: string;
+  form: NotifierForm[];
+}
+
+export interface NotificationSchedule {
+  timeZone: string;
+  days?: string[];
+  startTime: string;
+  endTime: string;
+  criticalTags?: string[];
+  fallbackNotifierId?: string;
+}
+
 export interface NotifierForm {
   type: string;
   title: string;
   formControlName: string;
   placeholder: string;
   required: boolean;
+  format: string;
 }
@@ -1,5 +1,7 @@
 export type ServiceType = 'HTTP' | 'ICMP_PING';
 
+export type SloBurnState = '' | 'SLOW' | 'FAST';
+
 export interface Service {
   id?: string;
   name: string;
@@ -19,6 +21,31 @@ export interface Service {
   notifyAfterNumberOfFailures: number;
   continuouslySendNotifications: boolean;
   notifiers: string[];
+  tags?: string[];
+  enabled?: boolean;
+  parentIds?: string[];
+  escalationPolicyId?: string;
+  flappingThreshold?: number;
+  flappingWindow?: number;
+  isFlapping?: boolean;
+  notificationTemplates?: NotificationTemplate[];
+  sloTarget?: number;
+  sloWindowInDays?: number;
+  sloFastBurnRate?: number;
+  sloSlowBurnRate?: number;
+  sloAlertsOnly?: boolean;
+  sloBurnState?: SloBurnState;
+  latencyThresholdInMs?: number;
+  latencyPercentile?: number;
+  latencyWindow?: number;
+  latencyDeviation?: number;
+  isDegraded?: boolean;
   createdAt?: string;
   updatedAt?: string;
 }
+
+export interface NotificationTemplate {
+  notifierId: string;
+  upTemplate?: string;
+  downTemplate?: string;
+}
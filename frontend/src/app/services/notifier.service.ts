@@ -1,7 +1,7 @@
 import {Injectable} from '@angular/core';
 import {HttpClient} from '@angular/common/http';
 import {Observable} from 'rxjs';
-import {Notifier} from '../models/notifier.model';
+import {NotificationSchedule, Notifier, NotifierType} from '../models/notifier.model';
 import {Wrapper} from '../models/wrapper.model';
 import {map} from 'rxjs/operators';
 
@@ -20,8 +20,23 @@ export class NotifierService {
       );
   }
 
-  put(notifierId: string, data: any): Observable<Notifier> {
-    return this.http.put<Notifier>(`/api/notifiers/${notifierId}`, data);
+  types(): Observable<NotifierType[]> {
+    return this.http.get<Wrapper<NotifierType>>('/api/notifier-types')
+      .pipe(
+        map(wrapper => wrapper.data)
+      );
+  }
+
+  post(type: string, name: string, data: any): Observable<Notifier> {
+    return this.http.post<Notifier>('/api/notifiers', {type, name, data});
+  }
+
+  put(notifierId: string, name: string, data: any, rateLimit?: number, schedule?: NotificationSchedule): Observable<Notifier> {
+    return this.http.put<Notifier>(`/api/notifiers/${notifierId}`, {name, data, rateLimit, schedule});
+  }
+
+  delete(notifierId: string): Observable<void> {
+    return this.http.delete<void>(`/api/notifiers/${notifierId}`);
   }
 
   testUpTemplate(notifierId: string, data: any): Observable<void> {
@@ -35,6 +35,8 @@ export class CreateServiceComponent implements OnInit, OnDestroy {
     enableNotifications: new FormControl(true),
     notifyAfterNumberOfFailures: new FormControl(2, [Validators.min(0), Validators.max(20)]),
     continuouslySendNotifications: new FormControl(false),
+    flappingThreshold: new FormControl(0, [Validators.min(0)]),
+    flappingWindow: new FormControl(20, [Validators.min(0), Validators.max(100)]),
     notifiers: new FormControl(['global'])
   });
 
@@ -4,7 +4,7 @@ import {ErrorService} from '../../services/error.service';
 import {ActivatedRoute, Router} from '@angular/router';
 import {map, switchAll, switchMap, tap} from 'rxjs/operators';
 import {Observable, Subscription} from 'rxjs';
-import {Service, ServiceType} from '../../models/service.model';
+import {NotificationTemplate, Service, ServiceType} from '../../models/service.model';
 import {FormControl, FormGroup, Validators} from '@angular/forms';
 import {ApiError} from '../../models/api-error.model';
 import {NotifierService} from '../../services/notifier.service';
@@ -37,6 +37,8 @@ export class EditServiceComponent implements OnInit {
     enableNotifications: new FormControl(true),
     notifyAfterNumberOfFailures: new FormControl(2, [Validators.min(0), Validators.max(20)]),
     continuouslySendNotifications: new FormControl(false),
+    flappingThreshold: new FormControl(0, [Validators.min(0)]),
+    flappingWindow: new FormControl(20, [Validators.min(0), Validators.max(100)]),
     notifiers: new FormControl(['global'])
   });
 
@@ -44,6 +46,10 @@ export class EditServiceComponent implements OnInit {
 
   isLoading = false;
   serviceId = '';
+  notificationTemplates: NotificationTemplate[] = [];
+  slo: Partial<Service> = {};
+  latencyAlerts: Partial<Service> = {};
+  relations: Partial<Service> = {};
 
   notifiers$: Observable<Notifier[]>;
 
@@ -65,6 +71,25 @@ export class EditServiceComponent implements OnInit {
         switchMap(id => this.serviceService.get(id)),
         tap(service => {
           this.serviceId = service.id;
+          this.notificationTemplates = service.notificationTemplates || [];
+          this.slo = {
+            sloTarget: service.sloTarget,
+            sloWindowInDays: service.sloWindowInDays,
+            sloFastBurnRate: service.sloFastBurnRate,
+            sloSlowBurnRate: service.sloSlowBurnRate,
+            sloAlertsOnly: service.sloAlertsOnly
+          };
+          this.latencyAlerts = {
+            latencyThresholdInMs: service.latencyThresholdInMs,
+            latencyPercentile: service.latencyPercentile,
+            latencyWindow: service.latencyWindow,
+            latencyDeviation: service.latencyDeviation
+          };
+          this.relations = {
+            escalationPolicyId: service.escalationPolicyId,
+            tags: service.tags,
+            parentIds: service.parentIds
+          };
           this.setFormGroupValues(service);
         }),
         tap(() => this.isLoading = false)
@@ -102,6 +127,8 @@ export class EditServiceComponent implements OnInit {
     this.formGroup.get('enableNotifications').setValue(service.enableNotifications);
     this.formGroup.get('notifyAfterNumberOfFailures').setValue(service.notifyAfterNumberOfFailures);
     this.formGroup.get('continuouslySendNotifications').setValue(service.continuouslySendNotifications);
+    this.formGroup.get('flappingThreshold').setValue(service.flappingThreshold);
+    this.formGroup.get('flappingWindow').setValue(service.flappingWindow);
     this.formGroup.get('notifiers').setValue(service.notifiers);
   }
 
@@ -111,7 +138,10 @@ export class EditServiceComponent implements OnInit {
 
     this.disableFormAllFields();
 
-    const formValues = this.formGroup.value as Service;
+    const formValues = {
+      ...this.formGroup.value, ...this.relations, ...this.slo, ...this.latencyAlerts,
+      notificationTemplates: this.notificationTemplates
+    } as Service;
     this.serviceService.put(this.serviceId, formValues)
       .pipe(
         tap(() => this.isLoading = false),
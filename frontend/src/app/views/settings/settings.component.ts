@@ -1,7 +1,7 @@
 import {Component, OnInit} from '@angular/core';
 import {NotifierService} from '../../services/notifier.service';
 import {Observable} from 'rxjs';
-import {Notifier} from '../../models/notifier.model';
+import {Notifier, NotifierType} from '../../models/notifier.model';
 import {FormBuilder, FormGroup} from '@angular/forms';
 import {tap} from 'rxjs/operators';
 import {Router} from '@angular/router';
@@ -14,6 +14,7 @@ import {Router} from '@angular/router';
 export class SettingsComponent implements OnInit {
 
   notifiers$: Observable<Notifier[]>;
+  notifierTypes$: Observable<NotifierType[]>;
   selectedNotifier: Notifier;
 
   constructor(private notifierService: NotifierService) {
@@ -22,6 +23,7 @@ export class SettingsComponent implements OnInit {
 
   ngOnInit(): void {
     this.loadNotifiers();
+    this.notifierTypes$ = this.notifierService.types();
   }
 
   loadNotifiers(): void {
@@ -33,6 +35,11 @@ export class SettingsComponent implements OnInit {
     this.selectedNotifier = notifier;
   }
 
+  // a notifier without id is created when it is saved
+  notifierTypeSelected(notifierType: NotifierType): void {
+    this.selectedNotifier = {id: '', type: notifierType.type, name: notifierType.name, data: {}, form: notifierType.form};
+  }
+
   resetSelectedNotifier(): void {
     this.selectedNotifier = null;
   }